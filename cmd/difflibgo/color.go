@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+// the same colors as difflibgo.UnifiedDiffColorized, plus bold headers and cyan hunk markers.
+const (
+	bold   = "\033[1m"
+	cyan   = "\033[96m"
+	yellow = "\033[93m"
+	red    = "\033[91m"
+	green  = "\033[92m"
+	end    = "\033[0m"
+)
+
+// colorWriter colorizes the diff written to it line by line -- deletions red, insertions green,
+// changes and "?" hint lines yellow, headers bold and hunk markers cyan. Word diffs are not
+// colored by a colorWriter, see colorWordDiffLine.
+type colorWriter struct {
+	w        io.Writer
+	format   difflibgo.Format
+	inHeader bool
+	pending  []byte
+}
+
+func newColorWriter(w io.Writer, format difflibgo.Format) *colorWriter {
+	return &colorWriter{
+		w:        w,
+		format:   format,
+		inHeader: format == difflibgo.FormatUnified || format == difflibgo.FormatContext,
+	}
+}
+
+func (c *colorWriter) Write(p []byte) (int, error) {
+	c.pending = append(c.pending, p...)
+
+	for {
+		idx := bytes.IndexByte(c.pending, '\n')
+		if idx == -1 {
+			return len(p), nil
+		}
+
+		if err := c.writeLine(string(c.pending[:idx+1])); err != nil {
+			return 0, err
+		}
+
+		c.pending = c.pending[idx+1:]
+	}
+}
+
+// Flush writes out the last line if it had no trailing newline.
+func (c *colorWriter) Flush() error {
+	if len(c.pending) == 0 {
+		return nil
+	}
+
+	err := c.writeLine(string(c.pending))
+
+	c.pending = nil
+
+	return err
+}
+
+func (c *colorWriter) writeLine(line string) error {
+	color := c.color(line)
+	if color == "" {
+		_, err := io.WriteString(c.w, line)
+
+		return err
+	}
+
+	// end the color before the newline so it never bleeds into the next line
+	text := strings.TrimSuffix(line, "\n")
+
+	_, err := io.WriteString(c.w, color+text+end+line[len(text):])
+
+	return err
+}
+
+func (c *colorWriter) color(line string) string {
+	switch c.format {
+	case difflibgo.FormatUnified:
+		// content lines may look like headers ("---" is the deletion of "--"), but headers only
+		// come before the first hunk
+		if strings.HasPrefix(line, "@@") {
+			c.inHeader = false
+
+			return cyan
+		}
+
+		if c.inHeader {
+			return bold
+		}
+
+		return prefixColor(line[:1], "-", "+", "")
+	case difflibgo.FormatContext:
+		// the context content lines always have a two character prefix, so the control lines
+		// are unambiguous
+		if strings.HasPrefix(line, "***") || strings.HasPrefix(line, "---") {
+			if c.inHeader {
+				c.inHeader = !strings.HasPrefix(line, "---")
+
+				return bold
+			}
+
+			return cyan
+		}
+
+		return prefixColor(prefix(line), "- ", "+ ", "! ")
+	case difflibgo.FormatNdiff:
+		return prefixColor(prefix(line), "- ", "+ ", "? ")
+	case difflibgo.FormatWordDiff:
+		// the markers can not be told apart from the same characters in the content
+	}
+
+	return ""
+}
+
+// colorWordDiffLine renders wordDiffLine as its String method does, with the deleted text (and
+// its markers) red and the inserted text green. The line is colored by its segments, so content
+// that happens to look like the markers is left alone.
+func colorWordDiffLine(wordDiffLine difflibgo.WordDiffLine) string {
+	var rendered strings.Builder
+
+	lineTerminator := ""
+
+	for _, segment := range wordDiffLine.Segments {
+		text, terminator := splitLineEnding(segment.Text)
+
+		var color, start, stop string
+
+		switch segment.Tag {
+		case difflibgo.OpDelete:
+			color, start, stop = red, "[-", "-]"
+		case difflibgo.OpInsert:
+			color, start, stop = green, "{+", "+}"
+		default:
+			rendered.WriteString(text)
+
+			if terminator != "" {
+				lineTerminator = terminator
+			}
+
+			continue
+		}
+
+		// a changed line terminator is shown escaped, the line itself is terminated once
+		if text == "" && terminator != "" {
+			text = strings.Trim(strconv.Quote(terminator), `"`)
+		}
+
+		if text != "" {
+			rendered.WriteString(color + start + text + stop + end)
+		}
+
+		if terminator != "" && (lineTerminator == "" || segment.Tag == difflibgo.OpInsert) {
+			lineTerminator = terminator
+		}
+	}
+
+	return rendered.String() + lineTerminator
+}
+
+// splitLineEnding splits line into its text and its "\n" or "\r\n" terminator, if any.
+func splitLineEnding(line string) (text, terminator string) {
+	switch {
+	case strings.HasSuffix(line, "\r\n"):
+		return line[:len(line)-2], "\r\n"
+	case strings.HasSuffix(line, "\n"):
+		return line[:len(line)-1], "\n"
+	}
+
+	return line, ""
+}
+
+func prefix(line string) string {
+	if len(line) < 2 { //nolint:gomnd
+		return line
+	}
+
+	return line[:2]
+}
+
+func prefixColor(linePrefix, deletion, insertion, change string) string {
+	switch linePrefix {
+	case deletion:
+		return red
+	case insertion:
+		return green
+	case change:
+		return yellow
+	}
+
+	return ""
+}
@@ -0,0 +1,369 @@
+// Command difflibgo compares two files (or stdin, given as "-") and writes their diff to stdout,
+// just as the difflibgo package would render it. The exit status is that of diff: 0 if the inputs
+// are the same, 1 if they differ and 2 if there was trouble. The html diff compares the lines as
+// they are, so --html can not be combined with the flags that ignore changes.
+//
+//	usage: difflibgo [-u | -c | -n | --word-diff | --html] [-U N] [-b] [-w] [-B] [-I RE]...
+//	                 [--strip-trailing-cr] [--color=auto|always|never] fromfile tofile
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+const (
+	exitSame      = 0
+	exitDifferent = 1
+	exitTrouble   = 2
+)
+
+const (
+	colorAuto   = "auto"
+	colorAlways = "always"
+	colorNever  = "never"
+)
+
+// fileDateLayout is the timestamp format diff uses in the file headers.
+const fileDateLayout = "2006-01-02 15:04:05.000000000 -0700"
+
+const stdinName = "-"
+
+var errStdinTwice = errors.New("stdin can only be compared against a file")
+
+type config struct {
+	format     difflibgo.Format
+	html       bool
+	htmlTable  bool
+	context    int
+	color      string
+	ignore     []difflibgo.Option
+	fromFile   string
+	toFile     string
+	isTerminal func() bool
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run is the actual command, returning the exit status rather than exiting so it can be tested.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c, err := parseArgs(args, stderr)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitSame
+		}
+
+		return exitTrouble
+	}
+
+	c.isTerminal = func() bool { return isTerminal(stdout) }
+
+	different, err := c.diff(stdin, stdout)
+	if err != nil {
+		fmt.Fprintf(stderr, "difflibgo: %v\n", err)
+
+		return exitTrouble
+	}
+
+	if different {
+		return exitDifferent
+	}
+
+	return exitSame
+}
+
+func parseArgs(args []string, stderr io.Writer) (*config, error) {
+	flags := flag.NewFlagSet("difflibgo", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+
+	flags.Usage = func() {
+		fmt.Fprintln(
+			stderr,
+			"usage: difflibgo [-u | -c | -n | --word-diff | --html] [-U N] [-b] [-w] [-B] "+
+				"[-I RE]... [--strip-trailing-cr] [--color=auto|always|never] fromfile tofile",
+		)
+		flags.PrintDefaults()
+	}
+
+	unified := flags.Bool("u", false, "produce a unified diff (the default)")
+	context := flags.Bool(
+		"c",
+		false,
+		"produce a context diff, or with --html only show the changes plus context",
+	)
+	ndiff := flags.Bool("n", false, "produce an ndiff (python difflib Differ style) diff")
+	wordDiff := flags.Bool("word-diff", false, "produce a word diff, [-removed-]{+added+} inline")
+	html := flags.Bool("html", false, "produce a side by side html diff")
+	contextLines := flags.Int("U", 3, "number of context lines") //nolint:gomnd
+	ignoreSpaceChange := flags.Bool("b", false, "ignore changes in the amount of whitespace")
+	ignoreAllSpace := flags.Bool("w", false, "ignore all whitespace")
+	ignoreBlankLines := flags.Bool("B", false, "ignore changes whose lines are all blank")
+	stripTrailingCR := flags.Bool("strip-trailing-cr", false, "compare CRLF line endings as LF")
+
+	var ignorePatterns []*regexp.Regexp
+
+	flags.Func(
+		"I",
+		"ignore changes whose lines all match the regular expression `RE`, may be repeated",
+		func(pattern string) error {
+			re, err := regexp.Compile(pattern)
+			if err != nil {
+				return err
+			}
+
+			ignorePatterns = append(ignorePatterns, re)
+
+			return nil
+		},
+	)
+
+	color := flags.String("color", colorNever, "colorize the output: auto, always or never")
+
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+
+	c := &config{
+		context: *contextLines,
+		color:   *color,
+		ignore: []difflibgo.Option{
+			difflibgo.WithIgnoreSpaceChange(*ignoreSpaceChange),
+			difflibgo.WithIgnoreAllSpace(*ignoreAllSpace),
+			difflibgo.WithIgnoreBlankLines(*ignoreBlankLines),
+			difflibgo.WithIgnoreMatching(ignorePatterns...),
+			difflibgo.WithNormalizeLineEndings(*stripTrailingCR),
+		},
+	}
+
+	switch {
+	case *html:
+		c.html = true
+		c.htmlTable = *context
+	case *ndiff:
+		c.format = difflibgo.FormatNdiff
+	case *wordDiff:
+		c.format = difflibgo.FormatWordDiff
+	case *context && !*unified:
+		c.format = difflibgo.FormatContext
+	}
+
+	// the html diff always compares the lines as they are, so the flags that say otherwise are
+	// rejected rather than silently ignored
+	var htmlIgnored string
+
+	flags.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "b", "w", "B", "I", "strip-trailing-cr":
+			htmlIgnored = f.Name
+		}
+	})
+
+	var err error
+
+	switch {
+	case c.html && htmlIgnored != "":
+		err = fmt.Errorf("-%s can not be combined with --html", htmlIgnored)
+	case c.color != colorAuto && c.color != colorAlways && c.color != colorNever:
+		err = fmt.Errorf("invalid --color %q, must be auto, always or never", c.color)
+	case c.context < 0:
+		err = fmt.Errorf("invalid -U %d, must not be negative", c.context)
+	case flags.NArg() != 2: //nolint:gomnd
+		err = errors.New("need exactly two files to compare")
+	case flags.Arg(0) == stdinName && flags.Arg(1) == stdinName:
+		err = errStdinTwice
+	}
+
+	if err != nil {
+		fmt.Fprintf(stderr, "difflibgo: %v\n", err)
+		flags.Usage()
+
+		return nil, err
+	}
+
+	c.fromFile, c.toFile = flags.Arg(0), flags.Arg(1)
+
+	return c, nil
+}
+
+// input is one of the two sides being compared, with the name and date for the diff header.
+type input struct {
+	io.Reader
+	name string
+	date string
+}
+
+func openInput(name string, stdin io.Reader) (*input, func(), error) {
+	if name == stdinName {
+		return &input{
+			Reader: stdin,
+			name:   name,
+			date:   time.Now().Format(fileDateLayout),
+		}, func() {}, nil
+	}
+
+	f, err := os.Open(name) //nolint:gosec
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+
+		return nil, nil, err
+	}
+
+	if info.IsDir() {
+		_ = f.Close()
+
+		return nil, nil, fmt.Errorf("%s is a directory", name)
+	}
+
+	return &input{
+		Reader: f,
+		name:   name,
+		date:   info.ModTime().Format(fileDateLayout),
+	}, func() { _ = f.Close() }, nil
+}
+
+func (c *config) diff(stdin io.Reader, stdout io.Writer) (bool, error) {
+	from, closeFrom, err := openInput(c.fromFile, stdin)
+	if err != nil {
+		return false, err
+	}
+
+	defer closeFrom()
+
+	to, closeTo, err := openInput(c.toFile, stdin)
+	if err != nil {
+		return false, err
+	}
+
+	defer closeTo()
+
+	if c.html {
+		return c.diffHTML(from, to, stdout)
+	}
+
+	colorize := c.color == colorAlways || (c.color == colorAuto && c.isTerminal())
+
+	if colorize && c.format == difflibgo.FormatWordDiff {
+		return c.diffWordsColored(from, to, stdout)
+	}
+
+	w := io.Writer(stdout)
+
+	var colorW *colorWriter
+
+	if colorize {
+		colorW = newColorWriter(stdout, c.format)
+		w = colorW
+	}
+
+	opts := append([]difflibgo.Option{
+		difflibgo.WithFormat(c.format),
+		difflibgo.WithContext(c.context),
+		difflibgo.WithFromFile(from.name, from.date),
+		difflibgo.WithToFile(to.name, to.date),
+	}, c.ignore...)
+
+	different, err := difflibgo.DiffReaders(from, to, w, opts...)
+	if err != nil {
+		return different, err
+	}
+
+	if colorW != nil {
+		return different, colorW.Flush()
+	}
+
+	return different, nil
+}
+
+func (c *config) diffHTML(from, to *input, stdout io.Writer) (bool, error) {
+	fromLines, err := difflibgo.ReadLines(from)
+	if err != nil {
+		return false, err
+	}
+
+	toLines, err := difflibgo.ReadLines(to)
+	if err != nil {
+		return false, err
+	}
+
+	different := strings.Join(fromLines, "") != strings.Join(toLines, "")
+
+	_, err = io.WriteString(
+		stdout,
+		difflibgo.NewHTMLDiff().MakeFile(
+			fromLines,
+			toLines,
+			from.name,
+			to.name,
+			c.htmlTable,
+			c.context,
+		),
+	)
+
+	return different, err
+}
+
+// diffWordsColored writes the colored word diff of from and to -- unlike the other formats it is
+// colored from the WordDiffLine segments rather than the rendered lines, so both inputs are read
+// into memory up front.
+func (c *config) diffWordsColored(from, to *input, stdout io.Writer) (bool, error) {
+	fromLines, err := difflibgo.ReadLines(from)
+	if err != nil {
+		return false, err
+	}
+
+	toLines, err := difflibgo.ReadLines(to)
+	if err != nil {
+		return false, err
+	}
+
+	w := bufio.NewWriter(stdout)
+
+	different := false
+
+	for _, wordDiffLine := range difflibgo.WordDiffStructured(fromLines, toLines, c.ignore...) {
+		different = different || wordDiffLine.Changed()
+
+		line := colorWordDiffLine(wordDiffLine)
+
+		// as DiffReaders does, the last line is terminated even if it had no newline
+		if _, terminator := splitLineEnding(line); terminator == "" {
+			line += "\n"
+		}
+
+		if _, err = w.WriteString(line); err != nil {
+			return different, err
+		}
+	}
+
+	return different, w.Flush()
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,321 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeInput(t *testing.T, dir, name, content string, modTime time.Time) string {
+	t.Helper()
+
+	path := filepath.Join(dir, name)
+
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed writing input file: %v", err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("failed setting input file times: %v", err)
+	}
+
+	return path
+}
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Date(2023, 5, 17, 10, 30, 0, 0, time.UTC)
+	date := modTime.Local().Format(fileDateLayout)
+
+	a := writeInput(t, dir, "a", "hostname r1\ninterface Gi0/1\n description foo\n", modTime)
+	b := writeInput(t, dir, "b", "hostname r1\ninterface Gi0/22\n description foo\n", modTime)
+	c := writeInput(t, dir, "c", "hostname  r1 \ninterface Gi0/1\ndescription  foo\n", modTime)
+	d := writeInput(t, dir, "d", "hostname r1\r\ninterface Gi0/1\r\n description foo", modTime)
+	e := writeInput(t, dir, "e", "banner [-x-] {+y+} 1\n", modTime)
+	f := writeInput(t, dir, "f", "banner [-x-] {+y+} 2\n", modTime)
+	g := writeInput(t, dir, "g", "interface x y  \n", modTime)
+	h := writeInput(t, dir, "h", "interface x y\n", modTime)
+	i := writeInput(t, dir, "i", "a\fb\n", modTime)
+	j := writeInput(t, dir, "j", "a\fX\n", modTime)
+
+	cases := []struct {
+		name     string
+		args     []string
+		stdin    string
+		exitCode int
+		expected string
+	}{
+		{
+			name:     "same",
+			args:     []string{a, a},
+			exitCode: exitSame,
+		},
+		{
+			name:     "unified-default",
+			args:     []string{a, b},
+			exitCode: exitDifferent,
+			expected: "--- " + a + "\t" + date + "\n" +
+				"+++ " + b + "\t" + date + "\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" hostname r1\n" +
+				"-interface Gi0/1\n" +
+				"+interface Gi0/22\n" +
+				"  description foo\n",
+		},
+		{
+			name:     "unified-no-context",
+			args:     []string{"-u", "-U", "0", a, b},
+			exitCode: exitDifferent,
+			expected: "--- " + a + "\t" + date + "\n" +
+				"+++ " + b + "\t" + date + "\n" +
+				"@@ -2 +2 @@\n" +
+				"-interface Gi0/1\n" +
+				"+interface Gi0/22\n",
+		},
+		{
+			name:     "context",
+			args:     []string{"-c", "-U", "0", a, b},
+			exitCode: exitDifferent,
+			expected: "*** " + a + "\t" + date + "\n" +
+				"--- " + b + "\t" + date + "\n" +
+				"***************\n" +
+				"*** 2 ****\n" +
+				"! interface Gi0/1\n" +
+				"--- 2 ----\n" +
+				"! interface Gi0/22\n",
+		},
+		{
+			name:     "ndiff-stdin",
+			args:     []string{"-n", "-", b},
+			stdin:    "hostname r1\ninterface Gi0/1\n description foo\n",
+			exitCode: exitDifferent,
+			expected: "  hostname r1\n" +
+				"- interface Gi0/1\n" +
+				"?               ^\n" +
+				"+ interface Gi0/22\n" +
+				"?               ^^\n" +
+				"   description foo\n",
+		},
+		{
+			name:     "ndiff-color",
+			args:     []string{"-n", "--color=always", a, b},
+			exitCode: exitDifferent,
+			expected: "  hostname r1\n" +
+				"\033[91m- interface Gi0/1\033[0m\n" +
+				"\033[93m?               ^\033[0m\n" +
+				"\033[92m+ interface Gi0/22\033[0m\n" +
+				"\033[93m?               ^^\033[0m\n" +
+				"   description foo\n",
+		},
+		{
+			name:     "unified-color",
+			args:     []string{"--color", "always", "-U", "0", a, b},
+			exitCode: exitDifferent,
+			expected: "\033[1m--- " + a + "\t" + date + "\033[0m\n" +
+				"\033[1m+++ " + b + "\t" + date + "\033[0m\n" +
+				"\033[96m@@ -2 +2 @@\033[0m\n" +
+				"\033[91m-interface Gi0/1\033[0m\n" +
+				"\033[92m+interface Gi0/22\033[0m\n",
+		},
+		{
+			name:     "color-auto-not-a-terminal",
+			args:     []string{"--color=auto", "-n", a, b},
+			exitCode: exitDifferent,
+			expected: "  hostname r1\n" +
+				"- interface Gi0/1\n" +
+				"?               ^\n" +
+				"+ interface Gi0/22\n" +
+				"?               ^^\n" +
+				"   description foo\n",
+		},
+		{
+			name:     "word-diff",
+			args:     []string{"--word-diff", a, b},
+			exitCode: exitDifferent,
+			expected: "hostname r1\n" +
+				"interface Gi0/[-1-]{+22+}\n" +
+				" description foo\n",
+		},
+		{
+			name:     "word-diff-color",
+			args:     []string{"--word-diff", "--color=always", a, b},
+			exitCode: exitDifferent,
+			expected: "hostname r1\n" +
+				"interface Gi0/\033[91m[-1-]\033[0m\033[92m{+22+}\033[0m\n" +
+				" description foo\n",
+		},
+		{
+			name:     "word-diff-color-markers-in-content",
+			args:     []string{"--word-diff", "--color=always", e, f},
+			exitCode: exitDifferent,
+			expected: "banner [-x-] {+y+} \033[91m[-1-]\033[0m\033[92m{+2+}\033[0m\n",
+		},
+		{
+			name:     "word-diff-trailing-whitespace",
+			args:     []string{"--word-diff", g, h},
+			exitCode: exitDifferent,
+			expected: "interface x y[-  -]\n",
+		},
+		{
+			name:     "word-diff-color-trailing-whitespace",
+			args:     []string{"--word-diff", "--color=always", g, h},
+			exitCode: exitDifferent,
+			expected: "interface x y\033[91m[-  -]\033[0m\n",
+		},
+		{
+			name:     "word-diff-form-feed",
+			args:     []string{"--word-diff", i, j},
+			exitCode: exitDifferent,
+			expected: "a\f[-b-]{+X+}\n",
+		},
+		{
+			name:     "word-diff-color-form-feed",
+			args:     []string{"--word-diff", "--color=always", i, j},
+			exitCode: exitDifferent,
+			expected: "a\f\033[91m[-b-]\033[0m\033[92m{+X+}\033[0m\n",
+		},
+		{
+			name:     "ignore-all-space",
+			args:     []string{"-w", a, c},
+			exitCode: exitSame,
+		},
+		{
+			name:     "ignore-space-change",
+			args:     []string{"-b", "-U", "0", a, c},
+			exitCode: exitDifferent,
+			expected: "--- " + a + "\t" + date + "\n" +
+				"+++ " + c + "\t" + date + "\n" +
+				"@@ -3 +3 @@\n" +
+				"- description foo\n" +
+				"+description  foo\n",
+		},
+		{
+			name:     "ignore-matching",
+			args:     []string{"-I", "^interface", "-I", "^hostname", a, c},
+			exitCode: exitDifferent,
+			expected: "--- " + a + "\t" + date + "\n" +
+				"+++ " + c + "\t" + date + "\n" +
+				"@@ -1,3 +1,3 @@\n" +
+				" hostname r1\n" +
+				" interface Gi0/1\n" +
+				"- description foo\n" +
+				"+description  foo\n",
+		},
+		{
+			name:     "strip-trailing-cr",
+			args:     []string{"--strip-trailing-cr", "-U", "0", a, d},
+			exitCode: exitDifferent,
+			expected: "--- " + a + "\t" + date + "\n" +
+				"+++ " + d + "\t" + date + "\n" +
+				"@@ -3 +3 @@\n" +
+				"- description foo\n" +
+				"+ description foo\n" +
+				"\\ No newline at end of file\n",
+		},
+		{
+			name:     "bad-ignore-pattern",
+			args:     []string{"-I", "(", a, b},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "missing-file",
+			args:     []string{a, filepath.Join(dir, "nope")},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "directory",
+			args:     []string{a, dir},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "one-file",
+			args:     []string{a},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "stdin-twice",
+			args:     []string{"-", "-"},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "bad-color",
+			args:     []string{"--color=sometimes", a, b},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "html-ignore-all-space",
+			args:     []string{"--html", "-w", a, c},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "html-strip-trailing-cr",
+			args:     []string{"--html", "--strip-trailing-cr", a, d},
+			exitCode: exitTrouble,
+		},
+		{
+			name:     "bad-flag",
+			args:     []string{"-x", a, b},
+			exitCode: exitTrouble,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				var stdout, stderr bytes.Buffer
+
+				exitCode := run(
+					testCase.args,
+					strings.NewReader(testCase.stdin),
+					&stdout,
+					&stderr,
+				)
+
+				if exitCode != testCase.exitCode {
+					t.Fatalf(
+						"expected exit code %d, got %d, stderr: %s",
+						testCase.exitCode,
+						exitCode,
+						stderr.String(),
+					)
+				}
+
+				if exitCode == exitTrouble && stderr.Len() == 0 {
+					t.Fatalf("expected an error message on stderr")
+				}
+
+				if stdout.String() != testCase.expected {
+					t.Fatalf(
+						"actual and expected do not match...\nactual:\n%s\nexpected:\n%s",
+						stdout.String(),
+						testCase.expected,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestRunHTML(t *testing.T) {
+	dir := t.TempDir()
+	modTime := time.Date(2023, 5, 17, 10, 30, 0, 0, time.UTC)
+
+	a := writeInput(t, dir, "a", "hostname r1\ninterface Gi0/1\n", modTime)
+	b := writeInput(t, dir, "b", "hostname r1\ninterface Gi0/22\n", modTime)
+
+	var stdout, stderr bytes.Buffer
+
+	if exitCode := run([]string{"--html", a, b}, nil, &stdout, &stderr); exitCode != exitDifferent {
+		t.Fatalf("expected exit code %d, got %d", exitDifferent, exitCode)
+	}
+
+	for _, expected := range []string{"<html>", "Gi0/<span class=\"diff_chg\">1</span>"} {
+		if !strings.Contains(stdout.String(), expected) {
+			t.Fatalf("expected html output to contain %q, got:\n%s", expected, stdout.String())
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package difflibgo
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	zeroWidthJoiner = '\u200d'
+	wideCharWidth   = 2
+)
+
+// splitRunes splits s into its runes, each as a string.
+func splitRunes(s string) []string {
+	chars := make([]string, 0, len(s))
+
+	for len(s) > 0 {
+		_, size := utf8.DecodeRuneInString(s)
+		chars = append(chars, s[:size])
+		s = s[size:]
+	}
+
+	return chars
+}
+
+// wordClass is the class of a character for splitWords.
+type wordClass int
+
+const (
+	wordClassOther wordClass = iota
+	wordClassWord
+	wordClassSpace
+)
+
+func classifyChar(c string) wordClass {
+	r, _ := utf8.DecodeRuneInString(c)
+
+	switch {
+	case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r):
+		return wordClassWord
+	case unicode.IsSpace(r):
+		return wordClassSpace
+	default:
+		return wordClassOther
+	}
+}
+
+// splitWords joins chars (runes or grapheme clusters) up into the tokens of a word diff -- runs of
+// letters, digits and underscores, runs of whitespace, and every other character on its own, so
+// "Gi0/1.100" is "Gi0", "/", "1", "." and "100".
+func splitWords(chars []string) []string {
+	words := make([]string, 0, len(chars))
+
+	for i := 0; i < len(chars); {
+		word, class := chars[i], classifyChar(chars[i])
+		i++
+
+		for class != wordClassOther && i < len(chars) && classifyChar(chars[i]) == class {
+			word += chars[i]
+			i++
+		}
+
+		words = append(words, word)
+	}
+
+	return words
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
+
+// extendsGrapheme returns true if r continues the grapheme cluster of which prev is the last
+// rune.
+func extendsGrapheme(prev, r rune) bool {
+	switch {
+	case prev == '\r' && r == '\n':
+		return true
+	case prev == zeroWidthJoiner:
+		return true
+	case r == zeroWidthJoiner:
+		return true
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		// combining marks, this includes the variation selectors
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		// emoji skin tone modifiers
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		// emoji tag sequences (subdivision flags)
+		return true
+	case r >= 0x1160 && r <= 0x11ff:
+		// hangul jamo vowels and trailing consonants
+		return prev >= 0x1100 && prev <= 0x11ff
+	}
+
+	return false
+}
+
+// splitGraphemes splits s into (an approximation of) its extended grapheme clusters -- base
+// characters with their combining marks, emoji zwj/modifier/tag sequences, flags (regional
+// indicator pairs), hangul jamo sequences and "\r\n". It does not implement every rule of
+// unicode's UAX #29, but covers the text one finds in configs and descriptions.
+func splitGraphemes(s string) []string {
+	var chars []string
+
+	for len(s) > 0 {
+		prev, size := utf8.DecodeRuneInString(s)
+		end := size
+
+		regionalIndicators := 0
+		if isRegionalIndicator(prev) {
+			regionalIndicators = 1
+		}
+
+		for end < len(s) {
+			r, size := utf8.DecodeRuneInString(s[end:])
+
+			if isRegionalIndicator(r) && regionalIndicators == 1 {
+				regionalIndicators++
+			} else if !extendsGrapheme(prev, r) {
+				break
+			}
+
+			prev = r
+			end += size
+		}
+
+		chars = append(chars, s[:end])
+		s = s[end:]
+	}
+
+	return chars
+}
+
+// runeWidth returns the number of terminal columns r occupies -- two for east asian wide and
+// full width characters (and most emoji), zero for combining, format and modifier characters,
+// one otherwise. This follows Markus Kuhn's wcwidth rather than the full unicode tables.
+func runeWidth(r rune) int {
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1160 && r <= 0x11ff, r >= 0x1f3fb && r <= 0x1f3ff:
+		// hangul jamo vowels and trailing consonants, emoji skin tone modifiers
+		return 0
+	case r >= 0x1100 && r <= 0x115f,
+		r == 0x2329, r == 0x232a,
+		r >= 0x2e80 && r <= 0xa4cf && r != 0x303f,
+		r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff,
+		r >= 0xfe10 && r <= 0xfe19,
+		r >= 0xfe30 && r <= 0xfe6f,
+		r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6,
+		r >= 0x1f300 && r <= 0x1f64f,
+		r >= 0x1f900 && r <= 0x1f9ff,
+		r >= 0x20000 && r <= 0x2fffd,
+		r >= 0x30000 && r <= 0x3fffd:
+		return wideCharWidth
+	}
+
+	return 1
+}
+
+// displayWidth returns the number of terminal columns the character (rune or grapheme cluster)
+// c occupies. Runes joined on to the cluster with a zero width joiner don't add to its width, a
+// zwj emoji sequence is rendered as a single emoji.
+func displayWidth(c string) int {
+	width := 0
+	joined := false
+
+	for _, r := range c {
+		if !joined {
+			width += runeWidth(r)
+		}
+
+		joined = r == zeroWidthJoiner
+	}
+
+	return width
+}
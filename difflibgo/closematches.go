@@ -0,0 +1,68 @@
+package difflibgo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+var (
+	// ErrInvalidCount is returned by GetCloseMatches when n is not greater than zero.
+	ErrInvalidCount = errors.New("n must be greater than zero")
+	// ErrInvalidCutoff is returned by GetCloseMatches when cutoff is not in the range [0, 1].
+	ErrInvalidCutoff = errors.New("cutoff must be in the range [0, 1]")
+)
+
+type closeMatch struct {
+	score float64
+	word  string
+}
+
+// GetCloseMatches is a port of python's difflib.get_close_matches -- it returns the (at most) n
+// best "good enough" matches for word from possibilities, best match first. A possibility is
+// good enough if its similarity ratio (see SequenceMatcher.Ratio) with word is at least cutoff;
+// python uses 3 and 0.6 for n and cutoff by default. Possibilities with the same score are
+// ordered like python orders them -- the greater string first. Words are compared rune by rune.
+func GetCloseMatches(word string, possibilities []string, n int, cutoff float64) ([]string, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidCount, n)
+	}
+
+	if cutoff < 0 || cutoff > 1 {
+		return nil, fmt.Errorf("%w, got %v", ErrInvalidCutoff, cutoff)
+	}
+
+	var matches []closeMatch
+
+	s := NewSequenceMatcher[rune](nil, nil)
+	s.SetSeq2([]rune(word))
+
+	for _, possibility := range possibilities {
+		s.SetSeq1([]rune(possibility))
+
+		// cheapest to most expensive, each is an upper bound of the next
+		if s.RealQuickRatio() >= cutoff && s.QuickRatio() >= cutoff && s.Ratio() >= cutoff {
+			matches = append(matches, closeMatch{score: s.Ratio(), word: possibility})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+
+		return matches[i].word > matches[j].word
+	})
+
+	if len(matches) > n {
+		matches = matches[:n]
+	}
+
+	closeMatches := make([]string, len(matches))
+
+	for idx, match := range matches {
+		closeMatches[idx] = match.word
+	}
+
+	return closeMatches, nil
+}
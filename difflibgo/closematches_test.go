@@ -0,0 +1,99 @@
+package difflibgo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestGetCloseMatches(t *testing.T) {
+	// expected matches are from python's difflib.get_close_matches
+	cases := []struct {
+		name          string
+		word          string
+		possibilities []string
+		n             int
+		cutoff        float64
+		expected      []string
+		expectedErr   error
+	}{
+		{
+			name:          "python-docs-example",
+			word:          "appel",
+			possibilities: []string{"ape", "apple", "peach", "puppy"},
+			n:             3,
+			cutoff:        0.6,
+			expected:      []string{"apple", "ape"},
+		},
+		{
+			name:          "best-first",
+			word:          "wheel",
+			possibilities: []string{"while", "wheel", "wheeled", "hello", "whelk"},
+			n:             4,
+			cutoff:        0.5,
+			expected:      []string{"wheel", "wheeled", "whelk", "while"},
+		},
+		{
+			name:          "ties-greater-string-first",
+			word:          "ab",
+			possibilities: []string{"ba", "ab2", "ca", "ac", "ad"},
+			n:             3,
+			cutoff:        0.5,
+			expected:      []string{"ab2", "ca", "ba"},
+		},
+		{
+			name:          "multi-byte-runes",
+			word:          "café",
+			possibilities: []string{"cafe", "cafés", "caff"},
+			n:             3,
+			cutoff:        0.6,
+			expected:      []string{"cafés", "caff", "cafe"},
+		},
+		{
+			name:          "no-matches",
+			word:          "xyz",
+			possibilities: []string{"abc", "def"},
+			n:             3,
+			cutoff:        0.6,
+			expected:      []string{},
+		},
+		{
+			name:        "invalid-count",
+			word:        "abc",
+			n:           0,
+			cutoff:      0.6,
+			expectedErr: difflibgo.ErrInvalidCount,
+		},
+		{
+			name:        "invalid-cutoff",
+			word:        "abc",
+			n:           3,
+			cutoff:      1.5,
+			expectedErr: difflibgo.ErrInvalidCutoff,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual, err := difflibgo.GetCloseMatches(
+					testCase.word,
+					testCase.possibilities,
+					testCase.n,
+					testCase.cutoff,
+				)
+
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+				}
+
+				if strings.Join(actual, "\n") != strings.Join(testCase.expected, "\n") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
@@ -1,10 +1,15 @@
 package difflibgo
 
+// OpCode tags, these are the first character of the tag names python's difflib uses.
 const (
-	insertOp  = 105
-	deleteOp  = 100
-	equalOp   = 101
-	replaceOp = 114
+	// OpInsert indicates that b[SeqBLo:SeqBHi] should be inserted at a[SeqALo:SeqALo].
+	OpInsert byte = 'i'
+	// OpDelete indicates that a[SeqALo:SeqAHi] should be deleted.
+	OpDelete byte = 'd'
+	// OpEqual indicates that a[SeqALo:SeqAHi] == b[SeqBLo:SeqBHi].
+	OpEqual byte = 'e'
+	// OpReplace indicates that a[SeqALo:SeqAHi] should be replaced by b[SeqBLo:SeqBHi].
+	OpReplace byte = 'r'
 )
 
 const (
@@ -12,10 +17,30 @@ const (
 	autoJunkLenHeuristic = 200
 )
 
+const (
+	defaultCutoff = 0.75
+	cutoffSlack   = 0.01
+)
+
+const (
+	defaultContext  = 3
+	defaultLineTerm = "\n"
+	defaultTabSize  = 8
+)
+
 const (
 	diffSubtraction = "- "
 	diffAddition    = "+ "
 	diffUnknown     = "? "
+	diffChange      = "! "
+	diffEqual       = "  "
+)
+
+const (
+	wordDeleteStart = "[-"
+	wordDeleteEnd   = "-]"
+	wordInsertStart = "{+"
+	wordInsertEnd   = "+}"
 )
 
 const (
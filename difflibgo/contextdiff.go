@@ -0,0 +1,136 @@
+package difflibgo
+
+import (
+	"fmt"
+)
+
+func formatRangeContext(start, stop int) string {
+	// lines start numbering with one
+	beginning := start + 1
+	length := stop - start
+
+	if length == 0 {
+		// empty ranges begin at line just before the range
+		beginning--
+	}
+
+	if length <= 1 {
+		return fmt.Sprintf("%d", beginning)
+	}
+
+	return fmt.Sprintf("%d,%d", beginning, beginning+length-1)
+}
+
+func contextDiffPrefix(tag byte) string {
+	switch tag {
+	case OpInsert:
+		return diffAddition
+	case OpDelete:
+		return diffSubtraction
+	case OpReplace:
+		return diffChange
+	default:
+		return diffEqual
+	}
+}
+
+func groupHasTag(group []OpCode, tags ...byte) bool {
+	for _, c := range group {
+		for _, tag := range tags {
+			if c.Tag == tag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ContextDiff compares a and b (slices of lines) and returns the delta as a context diff -- this
+// is a port of python's difflib.context_diff, and is the same format `diff -c` emits. Context
+// diffs show the changed lines plus a few lines of context (see WithContext), with "***" and
+// "---" control lines for each side of the change. As with UnifiedDiffLines the control lines are
+// terminated with the line terminator (see WithLineTerm) and the content lines are output as is.
+// If a and b are the same, the returned slice is empty.
+func ContextDiff(a, b []string, opts ...Option) []string {
+	o := newOptions(opts...)
+	o.lineEndings = hasLineEndings(a, b)
+
+	var diffLines []string
+
+	for _, group := range o.groupedOpcodes(a, b) {
+		if diffLines == nil {
+			diffLines = contextHeader(o)
+		}
+
+		diffLines = append(diffLines, contextHunk(a, b, group, 0, 0, o)...)
+	}
+
+	return diffLines
+}
+
+func contextHeader(o *options) []string {
+	return []string{
+		fileHeader("***", o.fromFile, o.fromFileDate, o.lineTerm),
+		fileHeader("---", o.toFile, o.toFileDate, o.lineTerm),
+	}
+}
+
+// contextHunk returns the lines of the context diff hunk for group, see unifiedHunk.
+func contextHunk(a, b []string, group []OpCode, offsetA, offsetB int, o *options) []string {
+	first, last := group[0], group[len(group)-1]
+
+	hunkLines := []string{
+		"***************" + o.lineTerm,
+		fmt.Sprintf(
+			"*** %s ****%s",
+			formatRangeContext(offsetA+first.SeqALo, offsetA+last.SeqAHi),
+			o.lineTerm,
+		),
+	}
+
+	if groupHasTag(group, OpReplace, OpDelete) {
+		for _, c := range group {
+			if c.Tag == OpInsert {
+				continue
+			}
+
+			hunkLines = appendHunkLines(
+				hunkLines,
+				contextDiffPrefix(c.Tag),
+				a,
+				c.SeqALo,
+				c.SeqAHi,
+				o,
+			)
+		}
+	}
+
+	hunkLines = append(
+		hunkLines,
+		fmt.Sprintf(
+			"--- %s ----%s",
+			formatRangeContext(offsetB+first.SeqBLo, offsetB+last.SeqBHi),
+			o.lineTerm,
+		),
+	)
+
+	if groupHasTag(group, OpReplace, OpInsert) {
+		for _, c := range group {
+			if c.Tag == OpDelete {
+				continue
+			}
+
+			hunkLines = appendHunkLines(
+				hunkLines,
+				contextDiffPrefix(c.Tag),
+				b,
+				c.SeqBLo,
+				c.SeqBHi,
+				o,
+			)
+		}
+	}
+
+	return hunkLines
+}
@@ -0,0 +1,108 @@
+package difflibgo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestContextDiff(t *testing.T) {
+	a := []string{
+		"one\n", "two\n", "three\n", "four\n", "five\n",
+		"six\n", "seven\n", "eight\n", "nine\n", "ten\n",
+	}
+	b := []string{
+		"one\n", "2\n", "three\n", "four\n", "five\n",
+		"six\n", "seven\n", "eight\n", "ten\n", "eleven\n",
+	}
+
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		opts     []difflibgo.Option
+		expected []string
+	}{
+		{
+			name:     "no-diff",
+			a:        a,
+			b:        a,
+			expected: nil,
+		},
+		{
+			name: "file-headers",
+			a:    a,
+			b:    b,
+			opts: []difflibgo.Option{
+				difflibgo.WithFromFile("a.txt", "2021-01-01"),
+				difflibgo.WithToFile("b.txt", ""),
+			},
+			expected: []string{
+				"*** a.txt\t2021-01-01\n",
+				"--- b.txt\n",
+				"***************\n",
+				"*** 1,10 ****\n",
+				"  one\n",
+				"! two\n",
+				"  three\n",
+				"  four\n",
+				"  five\n",
+				"  six\n",
+				"  seven\n",
+				"  eight\n",
+				"- nine\n",
+				"  ten\n",
+				"--- 1,10 ----\n",
+				"  one\n",
+				"! 2\n",
+				"  three\n",
+				"  four\n",
+				"  five\n",
+				"  six\n",
+				"  seven\n",
+				"  eight\n",
+				"  ten\n",
+				"+ eleven\n",
+			},
+		},
+		{
+			name: "zero-context",
+			a:    a,
+			b:    b,
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(0),
+			},
+			expected: []string{
+				"*** \n",
+				"--- \n",
+				"***************\n",
+				"*** 2 ****\n",
+				"! two\n",
+				"--- 2 ----\n",
+				"! 2\n",
+				"***************\n",
+				"*** 9 ****\n",
+				"- nine\n",
+				"--- 8 ----\n",
+				"***************\n",
+				"*** 10 ****\n",
+				"--- 10 ----\n",
+				"+ eleven\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.ContextDiff(testCase.a, testCase.b, testCase.opts...)
+
+				if strings.Join(actual, "") != strings.Join(testCase.expected, "") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
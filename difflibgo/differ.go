@@ -1,20 +1,48 @@
 package difflibgo
 
 import (
-	"fmt"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
 // Compare accepts a pair of string slices and returns a slice of their comparison -- this is just
-// an itty bitty helper function so you don't need to create a Differ object yourself since it has
-// no real value other than its Compare method.
+// an itty bitty helper function so you don't need to create a Differ object yourself if you don't
+// need any options.
 func Compare(seqA, seqB []string) []string {
 	d := Differ{}
 
 	return d.Compare(seqA, seqB)
 }
 
+// Ndiff is a port of python's difflib.ndiff -- it compares a and b with a Differ using python's
+// defaults, notably IsCharacterJunk as the charjunk function, and returns output identical to
+// python's. Options (WithLineJunk, WithCharJunk etc.) override the defaults as usual.
+func Ndiff(a, b []string, opts ...Option) []string {
+	return ndiffer(opts...).Compare(a, b)
+}
+
+// ndiffer returns a Differ set up with python's ndiff defaults, overridden by opts.
+func ndiffer(opts ...Option) *Differ {
+	return NewDiffer(append([]Option{WithCharJunk(IsCharacterJunk)}, opts...)...)
+}
+
+// CompareStructured compares seqA and seqB with a zero value Differ, like Compare, but returns the
+// comparison as DiffLines rather than pre-formatted strings.
+func CompareStructured(seqA, seqB []string) []DiffLine {
+	d := Differ{}
+
+	return d.CompareStructured(seqA, seqB)
+}
+
+// CompareFunc compares seqA and seqB with a zero value Differ and calls fn with each DiffLine as
+// soon as it is produced, stopping at (and returning) the first error fn returns.
+func CompareFunc(seqA, seqB []string, fn func(DiffLine) error) error {
+	d := Differ{}
+
+	return d.CompareFunc(seqA, seqB, fn)
+}
+
 func min(a, b int) int {
 	if a < b {
 		return a
@@ -23,6 +51,14 @@ func min(a, b int) int {
 	return b
 }
 
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
 func calculateRatio(matches, length int) float64 {
 	if length > 0 {
 		return 2.0 * float64(matches) / float64(length)
@@ -31,138 +67,354 @@ func calculateRatio(matches, length int) float64 {
 	return 1.0
 }
 
-type match struct {
-	A    int
-	B    int
-	Size int
+// isSpace is python's str.isspace for a single character, which unlike unicode.IsSpace also
+// considers the \x1c-\x1f separator characters whitespace.
+func isSpace(c rune) bool {
+	return unicode.IsSpace(c) || (c >= '\x1c' && c <= '\x1f')
 }
 
-type opCode struct {
-	Tag    byte
-	SeqALo int
-	SeqAHi int
-	SeqBLo int
-	SeqBHi int
+// IsLineJunk returns true if line is blank or contains only a single "#" (and whitespace); this
+// is python's difflib.IS_LINE_JUNK and is intended to be used with WithLineJunk.
+func IsLineJunk(line string) bool {
+	line = strings.TrimPrefix(strings.TrimLeftFunc(line, isSpace), "#")
+
+	return strings.TrimLeftFunc(line, isSpace) == ""
 }
 
-// Differ is an object that helps you compare two string slices.
-type Differ struct{}
+// IsCharacterJunk returns true if c is a space or a tab; this is python's
+// difflib.IS_CHARACTER_JUNK and is intended to be used with WithCharJunk.
+func IsCharacterJunk(c rune) bool {
+	return c == ' ' || c == '\t'
+}
 
-func (d *Differ) fancyHelper(seqALo, seqAHi, seqBLo, seqBHi int, seqA, seqB []string) []string {
-	var g []string
+// Differ is an object that helps you compare two string slices. The zero value is ready to use
+// and, like python's Differ, considers nothing to be junk; use NewDiffer to set options.
+type Differ struct {
+	lineJunk   func(string) bool
+	charJunk   func(rune) bool
+	noAutoJunk bool
+	cutoff     *float64
 
-	if seqALo < seqAHi {
-		if seqBLo < seqBHi {
-			g = d.fancyReplace(seqALo, seqAHi, seqBLo, seqBHi, seqA, seqB)
-		} else {
-			g = d.dump("-", seqA, seqALo, seqAHi)
-		}
-	} else if seqBLo < seqBHi {
-		g = d.dump("+", seqB, seqBLo, seqBHi)
+	graphemes    bool
+	displayWidth bool
+	words        bool
+
+	algorithm Algorithm
+
+	lineKey func(string) string
+	ignored func(string) bool
+
+	// emitMerged, if set, gets the paired up similar lines of a replaced block merged into one
+	// WordDiffLine rather than them being emitted as delete/insert lines with "?" guides
+	emitMerged func(WordDiffLine) error
+}
+
+// NewDiffer returns a Differ configured with the given options, see WithLineJunk, WithCharJunk,
+// WithAutoJunk, WithCutoff, WithGraphemeClusters, WithDisplayWidth, WithWordDiff, WithAlgorithm
+// and the options that make lines compare equal: WithIgnoreSpaceChange, WithIgnoreAllSpace,
+// WithIgnoreBlankLines, WithLineKey, WithIgnoreMatching and WithMaskMatching.
+func NewDiffer(opts ...Option) *Differ {
+	return newDiffer(newOptions(opts...))
+}
+
+func newDiffer(o *options) *Differ {
+	return &Differ{
+		lineJunk:   o.lineJunk,
+		charJunk:   o.charJunk,
+		noAutoJunk: o.noAutoJunk,
+		cutoff:     o.cutoff,
+
+		graphemes:    o.graphemes,
+		displayWidth: o.displayWidth,
+		words:        o.words,
+
+		algorithm: o.algorithm,
+
+		lineKey: o.key(),
+		ignored: o.ignored(),
+	}
+}
+
+// cutoffRatios returns the initial "best" ratio and the cutoff ratio fancyReplace uses to decide
+// if a pair of lines are similar enough to sync up on; python hardcodes these to 0.74 and 0.75,
+// the best ratio always sits just under the cutoff so that a pair exactly at the cutoff counts.
+func (d *Differ) cutoffRatios() (bestRatio, cutoffRatio float64) {
+	cutoffRatio = defaultCutoff
+	if d.cutoff != nil {
+		cutoffRatio = *d.cutoff
 	}
 
-	return g
+	return cutoffRatio - cutoffSlack, cutoffRatio
 }
 
-func (d *Differ) dump(tag string, sequence []string, lo, hi int) []string {
-	var dumper []string
+func (d *Differ) lineMatcher(seqA, seqB []string) *SequenceMatcher[string] {
+	opts := []MatcherOption[string]{
+		WithMatcherAutoJunk[string](!d.noAutoJunk),
+		WithMatcherAlgorithm[string](d.algorithm),
+		WithMatcherKey(d.lineKey),
+	}
 
-	for i := lo; i < hi; i++ {
-		dumper = append(dumper, fmt.Sprintf("%s %s", tag, sequence[i]))
+	if d.lineJunk != nil {
+		opts = append(opts, WithMatcherIsJunk(d.lineJunk))
 	}
 
-	return dumper
+	return NewSequenceMatcher(seqA, seqB, opts...)
 }
 
-func keepOriginalWs(s, tags string) string {
-	var strippedS string
+// key returns the key line is compared by, see WithLineKey.
+func (d *Differ) key(line string) string {
+	if d.lineKey == nil {
+		return line
+	}
 
-	var iterLen int
+	return d.lineKey(line)
+}
 
-	if len(s) > len(tags) {
-		iterLen = len(tags)
-	} else {
-		iterLen = len(s)
+// charMatcher returns the matcher for the intraline comparison of similar lines, its elements are
+// the "characters" of the lines -- see splitChars.
+func (d *Differ) charMatcher() *SequenceMatcher[string] {
+	opts := []MatcherOption[string]{
+		WithMatcherAutoJunk[string](!d.noAutoJunk),
+		WithMatcherAlgorithm[string](d.algorithm),
+	}
+
+	if d.charJunk != nil {
+		opts = append(opts, WithMatcherIsJunk(func(c string) bool {
+			r, size := utf8.DecodeRuneInString(c)
+
+			// a grapheme cluster of more than one rune is never junk
+			return size == len(c) && d.charJunk(r)
+		}))
+	}
+
+	return NewSequenceMatcher[string](nil, nil, opts...)
+}
+
+// splitChars splits s into the "characters" the intraline comparison works on -- runes or, if
+// enabled, grapheme clusters, or in word diff mode the words made up of those.
+func (d *Differ) splitChars(s string) []string {
+	if d.words {
+		return splitWords(d.splitCharacters(s))
+	}
+
+	return d.splitCharacters(s)
+}
+
+// splitCharacters splits s into runes or, if enabled, grapheme clusters.
+func (d *Differ) splitCharacters(s string) []string {
+	if d.graphemes {
+		return splitGraphemes(s)
+	}
+
+	return splitRunes(s)
+}
+
+// charWidth returns the number of "?" guide line columns for the "character" c, see splitChars.
+func (d *Differ) charWidth(c string) int {
+	if !d.words {
+		return d.characterWidth(c)
+	}
+
+	width := 0
+
+	for _, character := range d.splitCharacters(c) {
+		width += d.characterWidth(character)
+	}
+
+	return width
+}
+
+func (d *Differ) characterWidth(c string) int {
+	if d.displayWidth {
+		return displayWidth(c)
+	}
+
+	return 1
+}
+
+func (d *Differ) fancyHelper(
+	seqALo, seqAHi, seqBLo, seqBHi int,
+	seqA, seqB []string,
+	emit func(DiffLine) error,
+) error {
+	if seqALo < seqAHi {
+		if seqBLo < seqBHi {
+			return d.fancyReplace(seqALo, seqAHi, seqBLo, seqBHi, seqA, seqB, emit)
+		}
+
+		return d.dump(LineDelete, seqA, seqALo, seqAHi, emit)
+	}
+
+	if seqBLo < seqBHi {
+		return d.dump(LineInsert, seqB, seqBLo, seqBHi, emit)
+	}
+
+	return nil
+}
+
+// dump emits sequence[lo:hi] as delete or insert lines.
+func (d *Differ) dump(
+	kind LineKind,
+	sequence []string,
+	lo, hi int,
+	emit func(DiffLine) error,
+) error {
+	for i := lo; i < hi; i++ {
+		diffLine := DiffLine{Kind: kind, Text: sequence[i]}
+
+		if kind == LineDelete {
+			diffLine.LineA = i + 1
+		} else {
+			diffLine.LineB = i + 1
+		}
+
+		if err := emit(diffLine); err != nil {
+			return err
+		}
 	}
 
-	for i := 0; i < iterLen; i++ {
-		c, tagC := s[i], tags[i]
+	return nil
+}
+
+// keepOriginalWs returns the guide line for chars given a tag per character; whitespace
+// characters tagged as unchanged are kept as is so tabs and the like still line up.
+func (d *Differ) keepOriginalWs(chars []string, tags []byte) string {
+	var guide strings.Builder
+
+	for i := 0; i < len(chars) && i < len(tags); i++ {
+		c, tagC := chars[i], tags[i]
 
-		if string(tagC) == " " && unicode.IsSpace(rune(c)) {
-			strippedS += string(c)
+		if tagC == ' ' && strings.TrimLeftFunc(c, isSpace) == "" {
+			guide.WriteString(c)
 		} else {
-			strippedS += string(tagC)
+			guide.WriteString(strings.Repeat(string(tagC), d.charWidth(c)))
 		}
 	}
 
-	return strings.TrimRight(strippedS, " ")
+	return strings.TrimRightFunc(guide.String(), isSpace)
 }
 
-func (d *Differ) qFormat(aline, bline, atags, btags string) []string {
-	var f []string
+// charOffsets returns the byte offset of each of chars in the string they were split from, plus
+// the length of that string.
+func charOffsets(chars []string) []int {
+	offsets := make([]int, len(chars)+1)
 
-	atags = keepOriginalWs(aline, atags)
-	btags = keepOriginalWs(bline, btags)
+	for i, c := range chars {
+		offsets[i+1] = offsets[i] + len(c)
+	}
 
-	f = append(f, fmt.Sprintf("- %s", aline))
+	return offsets
+}
+
+func (d *Differ) qFormat(
+	aline, bline DiffLine,
+	atags, btags string,
+	emit func(DiffLine) error,
+) error {
+	diffLines := []DiffLine{aline}
 
 	if atags != "" {
-		f = append(f, fmt.Sprintf("? %s\n", atags))
+		diffLines = append(diffLines, DiffLine{Kind: LineHint, Text: atags, LineA: aline.LineA})
 	}
 
-	f = append(f, fmt.Sprintf("+ %s", bline))
+	diffLines = append(diffLines, bline)
 
 	if btags != "" {
-		f = append(f, fmt.Sprintf("? %s\n", btags))
+		diffLines = append(diffLines, DiffLine{Kind: LineHint, Text: btags, LineB: bline.LineB})
+	}
+
+	for _, diffLine := range diffLines {
+		if err := emit(diffLine); err != nil {
+			return err
+		}
 	}
 
-	return f
+	return nil
 }
 
-func (d *Differ) plainReplace(seqALo, seqAHi, seqBLo, seqBHi int, seqA, seqB []string) []string {
-	var first []string
+// dumpEqual emits the lines of an equal opCode.
+func (d *Differ) dumpEqual(opCode OpCode, seqA []string, emit func(DiffLine) error) error {
+	for i := 0; i < opCode.SeqAHi-opCode.SeqALo; i++ {
+		err := emit(DiffLine{
+			Kind:  LineEqual,
+			Text:  seqA[opCode.SeqALo+i],
+			LineA: opCode.SeqALo + i + 1,
+			LineB: opCode.SeqBLo + i + 1,
+		})
+		if err != nil {
+			return err
+		}
+	}
 
-	var second []string
+	return nil
+}
 
-	if seqBHi-seqBLo < seqAHi-seqALo {
-		first = d.dump("+", seqB, seqBLo, seqBHi)
-		second = d.dump("-", seqA, seqALo, seqAHi)
-	} else {
-		first = d.dump("-", seqA, seqALo, seqAHi)
-		second = d.dump("+", seqB, seqBLo, seqBHi)
+// dumpIgnored emits the lines of an opCode that only inserts or deletes ignored lines as
+// LineIgnored lines, the lines of seqA first.
+func (d *Differ) dumpIgnored(opCode OpCode, seqA, seqB []string, emit func(DiffLine) error) error {
+	for i := opCode.SeqALo; i < opCode.SeqAHi; i++ {
+		if err := emit(DiffLine{Kind: LineIgnored, Text: seqA[i], LineA: i + 1}); err != nil {
+			return err
+		}
 	}
 
-	return append(first, second...)
+	for j := opCode.SeqBLo; j < opCode.SeqBHi; j++ {
+		if err := emit(DiffLine{Kind: LineIgnored, Text: seqB[j], LineB: j + 1}); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
-func assembleFancyReplaceOutput(
-	preSyncPointDiffs, formattedTags, postSyncPointDiffs []string,
-) []string {
-	var finalOut []string
+func (d *Differ) plainReplace(
+	seqALo, seqAHi, seqBLo, seqBHi int,
+	seqA, seqB []string,
+	emit func(DiffLine) error,
+) error {
+	if seqBHi-seqBLo < seqAHi-seqALo {
+		if err := d.dump(LineInsert, seqB, seqBLo, seqBHi, emit); err != nil {
+			return err
+		}
 
-	finalOut = append(finalOut, preSyncPointDiffs...)
-	finalOut = append(finalOut, formattedTags...)
-	finalOut = append(finalOut, postSyncPointDiffs...)
+		return d.dump(LineDelete, seqA, seqALo, seqAHi, emit)
+	}
 
-	return finalOut
+	if err := d.dump(LineDelete, seqA, seqALo, seqAHi, emit); err != nil {
+		return err
+	}
+
+	return d.dump(LineInsert, seqB, seqBLo, seqBHi, emit)
 }
 
-func (d *Differ) fancyReplace(seqALo, seqAHi, seqBLo, seqBHi int, seqA, seqB []string) []string {
-	bestRatio, cutoffRatio := 0.74, 0.75
+func (d *Differ) fancyReplace( //nolint:funlen
+	seqALo, seqAHi, seqBLo, seqBHi int,
+	seqA, seqB []string,
+	emit func(DiffLine) error,
+) error {
+	bestRatio, cutoffRatio := d.cutoffRatios()
 	eqi, eqj := -1, -1
 	bestI, bestJ := -1, -1
 
-	s := &sequenceMatcher{}
+	s := d.charMatcher()
+
+	// lines are paired up on their keys, the characters of the keys are compared to find the
+	// most similar pair
+	aKeys := make([]string, seqAHi-seqALo)
+	aChars := make([][]string, seqAHi-seqALo)
+
+	for i := seqALo; i < seqAHi; i++ {
+		aKeys[i-seqALo] = d.key(seqA[i])
+		aChars[i-seqALo] = d.splitChars(aKeys[i-seqALo])
+	}
 
 	for j := seqBLo; j < seqBHi; j++ {
-		bj := seqB[j]
+		bKey := d.key(seqB[j])
 
-		s.setSequenceB([]string{bj})
+		s.SetSeq2(d.splitChars(bKey))
 
 		for i := seqALo; i < seqAHi; i++ {
-			ai := seqA[i]
-
-			if ai == bj {
+			if aKeys[i-seqALo] == bKey {
 				if eqi == -1 {
 					eqi, eqj = i, j
 				}
@@ -170,11 +422,11 @@ func (d *Differ) fancyReplace(seqALo, seqAHi, seqBLo, seqBHi int, seqA, seqB []s
 				continue
 			}
 
-			s.setSequenceA([]string{ai})
+			s.SetSeq1(aChars[i-seqALo])
 
-			if s.realQuickRatio() > bestRatio && s.quickRatio() > bestRatio &&
-				s.ratio() > bestRatio {
-				bestRatio = s.ratio()
+			if s.RealQuickRatio() > bestRatio && s.QuickRatio() > bestRatio &&
+				s.Ratio() > bestRatio {
+				bestRatio = s.Ratio()
 				bestI, bestJ = i, j
 			}
 		}
@@ -182,9 +434,7 @@ func (d *Differ) fancyReplace(seqALo, seqAHi, seqBLo, seqBHi int, seqA, seqB []s
 
 	if bestRatio < cutoffRatio {
 		if eqi == -1 {
-			replaced := d.plainReplace(seqALo, seqAHi, seqBLo, seqBHi, seqA, seqB)
-
-			return replaced
+			return d.plainReplace(seqALo, seqAHi, seqBLo, seqBHi, seqA, seqB, emit)
 		}
 
 		bestI, bestJ = eqi, eqj
@@ -192,85 +442,161 @@ func (d *Differ) fancyReplace(seqALo, seqAHi, seqBLo, seqBHi int, seqA, seqB []s
 		eqi = -1
 	}
 
-	preSyncPointDiffs := d.fancyHelper(seqALo, bestI, seqBLo, bestJ, seqA, seqB)
+	err := d.fancyHelper(seqALo, bestI, seqBLo, bestJ, seqA, seqB, emit)
+	if err != nil {
+		return err
+	}
 
 	aelt, belt := seqA[bestI], seqB[bestJ]
 
-	var formattedTags []string
+	switch {
+	case eqi == -1 && d.emitMerged != nil:
+		err = d.emitMerged(WordDiffLine{
+			LineA:    bestI + 1,
+			LineB:    bestJ + 1,
+			Segments: d.mergedSegments(s, aelt, belt),
+		})
+	case eqi == -1:
+		var atags, btags []byte
+
+		aline := DiffLine{Kind: LineDelete, Text: aelt, LineA: bestI + 1}
+		bline := DiffLine{Kind: LineInsert, Text: belt, LineB: bestJ + 1}
 
-	if eqi == -1 {
-		atags, btags := "", ""
+		aeltChars, beltChars := d.splitChars(aelt), d.splitChars(belt)
+		aOffsets, bOffsets := charOffsets(aeltChars), charOffsets(beltChars)
 
-		s.setSequences([]string{aelt}, []string{belt})
+		// if the keys line up character for character with the lines (say the key lowercases
+		// them) compare the keys, so that what the key ignores is not marked as changed
+		aKeyChars, bKeyChars := aChars[bestI-seqALo], d.splitChars(d.key(belt))
 
-		sequenceOpCodes := s.getOpcodes()
+		if len(aKeyChars) == len(aeltChars) && len(bKeyChars) == len(beltChars) {
+			s.SetSeqs(aKeyChars, bKeyChars)
+		} else {
+			s.SetSeqs(aeltChars, beltChars)
+		}
+
+		sequenceOpCodes := s.GetOpcodes()
 		for _, sequenceOpCode := range sequenceOpCodes {
 			la := sequenceOpCode.SeqAHi - sequenceOpCode.SeqALo
 			lb := sequenceOpCode.SeqBHi - sequenceOpCode.SeqBLo
 
+			aspan := Span{
+				sequenceOpCode.Tag,
+				aOffsets[sequenceOpCode.SeqALo],
+				aOffsets[sequenceOpCode.SeqAHi],
+			}
+			bspan := Span{
+				sequenceOpCode.Tag,
+				bOffsets[sequenceOpCode.SeqBLo],
+				bOffsets[sequenceOpCode.SeqBHi],
+			}
+
 			switch sequenceOpCode.Tag {
-			case replaceOp:
-				atags += strings.Repeat("^", la)
-				btags += strings.Repeat("^", lb)
-			case deleteOp:
-				atags += strings.Repeat("-", la)
-			case insertOp:
-				btags += strings.Repeat("+", lb)
-			case equalOp:
-				atags += strings.Repeat(" ", la)
-				btags += strings.Repeat(" ", lb)
+			case OpReplace:
+				atags = append(atags, strings.Repeat("^", la)...)
+				btags = append(btags, strings.Repeat("^", lb)...)
+
+				aline.Spans = append(aline.Spans, aspan)
+				bline.Spans = append(bline.Spans, bspan)
+			case OpDelete:
+				atags = append(atags, strings.Repeat("-", la)...)
+
+				aline.Spans = append(aline.Spans, aspan)
+			case OpInsert:
+				btags = append(btags, strings.Repeat("+", lb)...)
+
+				bline.Spans = append(bline.Spans, bspan)
+			case OpEqual:
+				atags = append(atags, strings.Repeat(" ", la)...)
+				btags = append(btags, strings.Repeat(" ", lb)...)
 			default:
 				panic("unknown opcode, this shouldn't happen...")
 			}
 		}
 
-		formattedTags = d.qFormat(aelt, belt, atags, btags)
-	} else {
-		formattedTags = []string{fmt.Sprintf("  %s", aelt)}
+		err = d.qFormat(
+			aline,
+			bline,
+			d.keepOriginalWs(aeltChars, atags),
+			d.keepOriginalWs(beltChars, btags),
+			emit,
+		)
+	default:
+		err = emit(DiffLine{Kind: LineEqual, Text: aelt, LineA: bestI + 1, LineB: bestJ + 1})
 	}
 
-	postSyncPointDiffs := d.fancyHelper(seqALo+1, seqAHi, seqBLo+1, seqBHi, seqA, seqB)
+	if err != nil {
+		return err
+	}
 
-	return assembleFancyReplaceOutput(preSyncPointDiffs, formattedTags, postSyncPointDiffs)
+	return d.fancyHelper(bestI+1, seqAHi, bestJ+1, seqBHi, seqA, seqB, emit)
 }
 
-// Compare accepts two string slices and compares them.
+// Compare accepts two string slices and compares them, returning the python difflib.Differ style
+// output -- each line prefixed with "- ", "+ ", "  " or "? ". This is just CompareStructured with
+// each DiffLine rendered with its String method.
 func (d *Differ) Compare(seqA, seqB []string) []string {
-	s := &sequenceMatcher{}
-	s.setSequences(
-		seqA,
-		seqB,
-	)
+	return renderDiffLines(d.CompareStructured(seqA, seqB))
+}
+
+// CompareStructured accepts two string slices and compares them, returning the comparison as
+// DiffLines rather than pre-formatted strings.
+func (d *Differ) CompareStructured(seqA, seqB []string) []DiffLine {
+	var diffLines []DiffLine
+
+	_ = d.CompareFunc(seqA, seqB, func(diffLine DiffLine) error {
+		diffLines = append(diffLines, diffLine)
+
+		return nil
+	})
 
-	opCodes := s.getOpcodes()
+	return diffLines
+}
+
+// CompareFunc compares seqA and seqB like CompareStructured, but rather than collecting the result
+// it calls fn with each DiffLine as soon as it is produced. If fn returns an error the comparison
+// stops right there and that error is returned, so callers can bail out after the first few
+// differences without paying for the rest of the diff.
+func (d *Differ) CompareFunc(seqA, seqB []string, fn func(DiffLine) error) error {
+	s := d.lineMatcher(seqA, seqB)
+
+	for _, curOpCode := range s.GetOpcodes() {
+		var err error
+
+		if curOpCode.Tag != OpEqual && d.ignored != nil &&
+			isIgnoredChange(curOpCode, seqA, seqB, d.ignored) {
+			if err := d.dumpIgnored(curOpCode, seqA, seqB, fn); err != nil {
+				return err
+			}
 
-	var finalOut []string
+			continue
+		}
 
-	for _, curOpCode := range opCodes {
 		switch curOpCode.Tag {
-		case replaceOp:
-			c := d.fancyReplace(
+		case OpReplace:
+			err = d.fancyReplace(
 				curOpCode.SeqALo,
 				curOpCode.SeqAHi,
 				curOpCode.SeqBLo,
 				curOpCode.SeqBHi,
 				seqA,
 				seqB,
+				fn,
 			)
-			finalOut = append(finalOut, c...)
-		case deleteOp:
-			c := d.dump("-", seqA, curOpCode.SeqALo, curOpCode.SeqAHi)
-			finalOut = append(finalOut, c...)
-		case insertOp:
-			c := d.dump("+", seqB, curOpCode.SeqBLo, curOpCode.SeqBHi)
-			finalOut = append(finalOut, c...)
-		case equalOp:
-			c := d.dump(" ", seqA, curOpCode.SeqALo, curOpCode.SeqAHi)
-			finalOut = append(finalOut, c...)
+		case OpDelete:
+			err = d.dump(LineDelete, seqA, curOpCode.SeqALo, curOpCode.SeqAHi, fn)
+		case OpInsert:
+			err = d.dump(LineInsert, seqB, curOpCode.SeqBLo, curOpCode.SeqBHi, fn)
+		case OpEqual:
+			err = d.dumpEqual(curOpCode, seqA, fn)
 		default:
 			panic("unknown opcode, this shouldn't happen...")
 		}
+
+		if err != nil {
+			return err
+		}
 	}
 
-	return finalOut
+	return nil
 }
@@ -1,6 +1,9 @@
 package difflibgo_test
 
 import (
+	"errors"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -68,10 +71,12 @@ func TestDifferCompare(t *testing.T) {
 			expected: []string{
 				"  abc",
 				"- defq",
+				"?    -\n",
 				"+ def",
 				"  123",
 				"- xyz",
 				"+ xyz9",
+				"?    +\n",
 			},
 		},
 		{
@@ -90,6 +95,7 @@ func TestDifferCompare(t *testing.T) {
 			expected: []string{
 				"  abc",
 				"- defq",
+				"?    -\n",
 				"+ def",
 				"  123",
 				"+ xyz9",
@@ -111,6 +117,7 @@ func TestDifferCompare(t *testing.T) {
 			expected: []string{
 				"  abc",
 				"- defq",
+				"?    -\n",
 				"+ def",
 				"  123",
 				"- xyz",
@@ -162,3 +169,753 @@ func TestDifferCompare(t *testing.T) {
 		)
 	}
 }
+
+func TestDifferCompareJunk(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []difflibgo.Option
+		a        []string
+		b        []string
+		expected []string
+	}{
+		{
+			name: "no-junk",
+			a:    []string{"#", ""},
+			b:    []string{"", "#"},
+			expected: []string{
+				"+ ",
+				"  #",
+				"- ",
+			},
+		},
+		{
+			name: "line-junk",
+			opts: []difflibgo.Option{difflibgo.WithLineJunk(difflibgo.IsLineJunk)},
+			a:    []string{"#", ""},
+			b:    []string{"", "#"},
+			expected: []string{
+				"- #",
+				"  ",
+				"+ #",
+			},
+		},
+		{
+			name: "char-junk",
+			opts: []difflibgo.Option{difflibgo.WithCharJunk(difflibgo.IsCharacterJunk)},
+			a:    []string{"a b  c d"},
+			b:    []string{"a  b c d "},
+			expected: []string{
+				"- a b  c d",
+				"?     -\n",
+				"+ a  b c d ",
+				"?   +     +\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.NewDiffer(testCase.opts...).Compare(testCase.a, testCase.b)
+
+				if strings.Join(actual, "\n") != strings.Join(testCase.expected, "\n") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestIsLineJunk(t *testing.T) {
+	for line, expected := range map[string]bool{
+		"":          true,
+		"\n":        true,
+		"  #   \n":  true,
+		"#":         true,
+		"##":        false,
+		" hi\n":     false,
+		"\v#\x1c\n": true,
+		"# x":       false,
+	} {
+		if difflibgo.IsLineJunk(line) != expected {
+			t.Fatalf("IsLineJunk(%q) expected %v", line, expected)
+		}
+	}
+}
+
+func TestDifferCompareCutoff(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []difflibgo.Option
+		a        []string
+		b        []string
+		expected []string
+	}{
+		{
+			name: "default-cutoff-not-similar",
+			a:    []string{"abc"},
+			b:    []string{"abd"},
+			expected: []string{
+				"- abc",
+				"+ abd",
+			},
+		},
+		{
+			name: "lower-cutoff-similar",
+			opts: []difflibgo.Option{difflibgo.WithCutoff(0.6)},
+			a:    []string{"abc"},
+			b:    []string{"abd"},
+			expected: []string{
+				"- abc",
+				"?   ^\n",
+				"+ abd",
+				"?   ^\n",
+			},
+		},
+		{
+			name: "default-cutoff-similar",
+			a:    []string{"interface Gi0/1"},
+			b:    []string{"interface Gi0/2"},
+			expected: []string{
+				"- interface Gi0/1",
+				"?               ^\n",
+				"+ interface Gi0/2",
+				"?               ^\n",
+			},
+		},
+		{
+			name: "intraline-hints-off",
+			opts: []difflibgo.Option{difflibgo.WithCutoff(1)},
+			a:    []string{"interface Gi0/1"},
+			b:    []string{"interface Gi0/2"},
+			expected: []string{
+				"- interface Gi0/1",
+				"+ interface Gi0/2",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.NewDiffer(testCase.opts...).Compare(testCase.a, testCase.b)
+
+				if strings.Join(actual, "\n") != strings.Join(testCase.expected, "\n") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestDifferCompareStructured(t *testing.T) {
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		expected []difflibgo.DiffLine
+	}{
+		{
+			name: "equal-delete-insert",
+			a:    []string{"abc", "def", "xyz"},
+			b:    []string{"abc", "xyz", "123"},
+			expected: []difflibgo.DiffLine{
+				{Kind: difflibgo.LineEqual, Text: "abc", LineA: 1, LineB: 1},
+				{Kind: difflibgo.LineDelete, Text: "def", LineA: 2},
+				{Kind: difflibgo.LineEqual, Text: "xyz", LineA: 3, LineB: 2},
+				{Kind: difflibgo.LineInsert, Text: "123", LineB: 3},
+			},
+		},
+		{
+			name: "intraline-change",
+			a:    []string{"interface Gi0/1", "description foo"},
+			b:    []string{"interface Gi0/22", "description foo"},
+			expected: []difflibgo.DiffLine{
+				{
+					Kind:  difflibgo.LineDelete,
+					Text:  "interface Gi0/1",
+					LineA: 1,
+					Spans: []difflibgo.Span{{Tag: difflibgo.OpReplace, Start: 14, End: 15}},
+				},
+				{Kind: difflibgo.LineHint, Text: "              ^", LineA: 1},
+				{
+					Kind:  difflibgo.LineInsert,
+					Text:  "interface Gi0/22",
+					LineB: 1,
+					Spans: []difflibgo.Span{{Tag: difflibgo.OpReplace, Start: 14, End: 16}},
+				},
+				{Kind: difflibgo.LineHint, Text: "              ^^", LineB: 1},
+				{Kind: difflibgo.LineEqual, Text: "description foo", LineA: 2, LineB: 2},
+			},
+		},
+		{
+			name: "multi-byte-spans",
+			a:    []string{"description 日本語の説明"},
+			b:    []string{"description 日本人の説明"},
+			expected: []difflibgo.DiffLine{
+				{
+					Kind:  difflibgo.LineDelete,
+					Text:  "description 日本語の説明",
+					LineA: 1,
+					Spans: []difflibgo.Span{{Tag: difflibgo.OpReplace, Start: 18, End: 21}},
+				},
+				{Kind: difflibgo.LineHint, Text: "              ^", LineA: 1},
+				{
+					Kind:  difflibgo.LineInsert,
+					Text:  "description 日本人の説明",
+					LineB: 1,
+					Spans: []difflibgo.Span{{Tag: difflibgo.OpReplace, Start: 18, End: 21}},
+				},
+				{Kind: difflibgo.LineHint, Text: "              ^", LineB: 1},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.CompareStructured(testCase.a, testCase.b)
+
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf("actual and expected do not match...\nactual:   %+v\nexpected: %+v",
+						actual, testCase.expected)
+				}
+
+				rendered := make([]string, len(actual))
+
+				for idx, diffLine := range actual {
+					rendered[idx] = diffLine.String()
+				}
+
+				expected := difflibgo.Compare(testCase.a, testCase.b)
+
+				if strings.Join(rendered, "") != strings.Join(expected, "") {
+					failOutput(t, rendered, expected)
+				}
+			},
+		)
+	}
+}
+
+func TestDifferCompareFunc(t *testing.T) {
+	errStop := errors.New("stop")
+
+	a := []string{"abc", "def", "interface Gi0/1", "xyz", "foo", "bar"}
+	b := []string{"abc", "interface Gi0/22", "xyz", "foo", "123", "baz"}
+
+	cases := []struct {
+		name    string
+		limit   int
+		visited int
+	}{
+		{
+			name:    "all-lines",
+			limit:   -1,
+			visited: 11,
+		},
+		{
+			name:    "first-difference",
+			limit:   1,
+			visited: 2,
+		},
+		{
+			name:    "stop-inside-intraline-change",
+			limit:   3,
+			visited: 4,
+		},
+		{
+			name:    "stop-at-last-line",
+			limit:   8,
+			visited: 11,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				var actual []difflibgo.DiffLine
+
+				differences := 0
+
+				err := difflibgo.CompareFunc(a, b, func(diffLine difflibgo.DiffLine) error {
+					actual = append(actual, diffLine)
+
+					if diffLine.Kind != difflibgo.LineEqual {
+						differences++
+					}
+
+					if differences == testCase.limit {
+						return errStop
+					}
+
+					return nil
+				})
+
+				if testCase.limit == -1 && err != nil {
+					t.Fatalf("expected no error, got: %v", err)
+				}
+
+				if testCase.limit != -1 && !errors.Is(err, errStop) {
+					t.Fatalf("expected errStop, got: %v", err)
+				}
+
+				expected := difflibgo.CompareStructured(a, b)[:testCase.visited]
+
+				if !reflect.DeepEqual(actual, expected) {
+					t.Fatalf("actual and expected do not match...\nactual:   %+v\nexpected: %+v",
+						actual, expected)
+				}
+			},
+		)
+	}
+}
+
+func TestDifferCompareWhitespace(t *testing.T) {
+	a := []string{"interface Gi0/1", " description  uplink ", "", "interface Gi0/2", " shutdown"}
+	b := []string{"interface Gi0/1", "  description uplink", "interface Gi0/2", " shutdown", "  "}
+
+	cases := []struct {
+		name     string
+		opts     []difflibgo.Option
+		expected []difflibgo.DiffLine
+	}{
+		{
+			name: "ignore-space-change",
+			opts: []difflibgo.Option{difflibgo.WithIgnoreSpaceChange(true)},
+			expected: []difflibgo.DiffLine{
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/1", LineA: 1, LineB: 1},
+				{Kind: difflibgo.LineEqual, Text: " description  uplink ", LineA: 2, LineB: 2},
+				{Kind: difflibgo.LineDelete, Text: "", LineA: 3},
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/2", LineA: 4, LineB: 3},
+				{Kind: difflibgo.LineEqual, Text: " shutdown", LineA: 5, LineB: 4},
+				{Kind: difflibgo.LineInsert, Text: "  ", LineB: 5},
+			},
+		},
+		{
+			name: "ignore-all-space",
+			opts: []difflibgo.Option{difflibgo.WithIgnoreAllSpace(true)},
+			expected: []difflibgo.DiffLine{
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/1", LineA: 1, LineB: 1},
+				{Kind: difflibgo.LineEqual, Text: " description  uplink ", LineA: 2, LineB: 2},
+				{Kind: difflibgo.LineDelete, Text: "", LineA: 3},
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/2", LineA: 4, LineB: 3},
+				{Kind: difflibgo.LineEqual, Text: " shutdown", LineA: 5, LineB: 4},
+				{Kind: difflibgo.LineInsert, Text: "  ", LineB: 5},
+			},
+		},
+		{
+			name: "ignore-blank-lines",
+			opts: []difflibgo.Option{difflibgo.WithIgnoreBlankLines(true)},
+			expected: []difflibgo.DiffLine{
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/1", LineA: 1, LineB: 1},
+				{
+					Kind:  difflibgo.LineDelete,
+					Text:  " description  uplink ",
+					LineA: 2,
+					Spans: []difflibgo.Span{
+						{Tag: difflibgo.OpDelete, Start: 13, End: 14},
+						{Tag: difflibgo.OpDelete, Start: 20, End: 21},
+					},
+				},
+				{Kind: difflibgo.LineHint, Text: "             -      -", LineA: 2},
+				{
+					Kind:  difflibgo.LineInsert,
+					Text:  "  description uplink",
+					LineB: 2,
+					Spans: []difflibgo.Span{{Tag: difflibgo.OpInsert, Start: 0, End: 1}},
+				},
+				{Kind: difflibgo.LineHint, Text: "+", LineB: 2},
+				{Kind: difflibgo.LineDelete, Text: "", LineA: 3},
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/2", LineA: 4, LineB: 3},
+				{Kind: difflibgo.LineEqual, Text: " shutdown", LineA: 5, LineB: 4},
+				{Kind: difflibgo.LineInsert, Text: "  ", LineB: 5},
+			},
+		},
+		{
+			name: "ignore-space-change-and-blank-lines",
+			opts: []difflibgo.Option{
+				difflibgo.WithIgnoreSpaceChange(true),
+				difflibgo.WithIgnoreBlankLines(true),
+			},
+			expected: []difflibgo.DiffLine{
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/1", LineA: 1, LineB: 1},
+				{Kind: difflibgo.LineEqual, Text: " description  uplink ", LineA: 2, LineB: 2},
+				{Kind: difflibgo.LineIgnored, Text: "", LineA: 3},
+				{Kind: difflibgo.LineEqual, Text: "interface Gi0/2", LineA: 4, LineB: 3},
+				{Kind: difflibgo.LineEqual, Text: " shutdown", LineA: 5, LineB: 4},
+				{Kind: difflibgo.LineIgnored, Text: "  ", LineB: 5},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.NewDiffer(testCase.opts...).CompareStructured(a, b)
+
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf("actual and expected do not match...\nactual:   %+v\nexpected: %+v",
+						actual, testCase.expected)
+				}
+
+				// lines that compare equal show the a side, so only a can be restored as is
+				delta := difflibgo.NewDiffer(testCase.opts...).Compare(a, b)
+
+				restored, err := difflibgo.Restore(delta, 1)
+				if err != nil || !reflect.DeepEqual(restored, a) {
+					t.Fatalf("expected to restore %q, got %q (%v)", a, restored, err)
+				}
+			},
+		)
+	}
+}
+
+func TestDifferCompareKey(t *testing.T) {
+	uptime := regexp.MustCompile(`uptime is .*`)
+	number := regexp.MustCompile(`\d+`)
+
+	maskCounters := func(line string) string {
+		line = uptime.ReplaceAllString(strings.ToLower(line), "uptime is <uptime>")
+
+		return number.ReplaceAllString(line, "<n>")
+	}
+
+	cases := []struct {
+		name     string
+		key      func(string) string
+		a        []string
+		b        []string
+		expected []string
+	}{
+		{
+			name: "mask-counters",
+			key:  maskCounters,
+			a: []string{
+				"Router uptime is 2 weeks, 1 day",
+				"Gi0/1 is up, 1024 packets input",
+				"INTERFACE GI0/3 IS UP",
+				"  MTU 1500 bytes",
+			},
+			b: []string{
+				"Router uptime is 2 weeks, 3 days",
+				"Gi0/1 is down, 2048 packets input",
+				"interface gi0/3 is down",
+				"  MTU 9000 bytes",
+			},
+			expected: []string{
+				"  Router uptime is 2 weeks, 1 day",
+				"- Gi0/1 is up, 1024 packets input",
+				"?          ^^  ^ -\n",
+				"+ Gi0/1 is down, 2048 packets input",
+				"?          ^^^^  ^  +\n",
+				"- INTERFACE GI0/3 IS UP",
+				"? ^^^^^^^^^ ^^    ^^ ^^\n",
+				"+ interface gi0/3 is down",
+				"? ^^^^^^^^^ ^^    ^^ ^^^^\n",
+				"    MTU 1500 bytes",
+			},
+		},
+		{
+			name: "lowercase",
+			key:  strings.ToLower,
+			a:    []string{"HOSTNAME R1", "INTERFACE GI0/3 IS UP"},
+			b:    []string{"hostname r1", "interface gi0/3 is down"},
+			expected: []string{
+				"  HOSTNAME R1",
+				"- INTERFACE GI0/3 IS UP",
+				"?                    ^^\n",
+				"+ interface gi0/3 is down",
+				"?                    ^^^^\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				d := difflibgo.NewDiffer(difflibgo.WithLineKey(testCase.key))
+
+				actual := d.Compare(testCase.a, testCase.b)
+
+				if strings.Join(actual, "\n") != strings.Join(testCase.expected, "\n") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestDifferCompareIgnoreMatching(t *testing.T) {
+	a := []string{"! Last change at 10:04:51", "hostname r1", "interface Gi0/1"}
+	b := []string{
+		"! Last change at 08:12:09", "! Updated at 08:12:10", "hostname r2", "interface Gi0/1",
+	}
+
+	d := difflibgo.NewDiffer(
+		difflibgo.WithIgnoreMatching(regexp.MustCompile(`^! .* at \d\d:\d\d:\d\d$`)),
+	)
+
+	expected := []difflibgo.DiffLine{
+		{Kind: difflibgo.LineEqual, Text: "! Last change at 10:04:51", LineA: 1, LineB: 1},
+		{Kind: difflibgo.LineInsert, Text: "! Updated at 08:12:10", LineB: 2},
+		{
+			Kind:  difflibgo.LineDelete,
+			Text:  "hostname r1",
+			LineA: 2,
+			Spans: []difflibgo.Span{{Tag: difflibgo.OpReplace, Start: 10, End: 11}},
+		},
+		{Kind: difflibgo.LineHint, Text: "          ^", LineA: 2},
+		{
+			Kind:  difflibgo.LineInsert,
+			Text:  "hostname r2",
+			LineB: 3,
+			Spans: []difflibgo.Span{{Tag: difflibgo.OpReplace, Start: 10, End: 11}},
+		},
+		{Kind: difflibgo.LineHint, Text: "          ^", LineB: 3},
+		{Kind: difflibgo.LineEqual, Text: "interface Gi0/1", LineA: 3, LineB: 4},
+	}
+
+	actual := d.CompareStructured(a, b)
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("actual and expected do not match...\nactual:   %+v\nexpected: %+v",
+			actual, expected)
+	}
+}
+
+func TestDifferCompareIgnoredInChange(t *testing.T) {
+	a := []string{"x", "", "a", "y"}
+	b := []string{"x", "b", "y"}
+
+	d := difflibgo.NewDiffer(difflibgo.WithIgnoreBlankLines(true))
+
+	actual := d.Compare(a, b)
+
+	// the blank line is part of a real change, so it is shown as deleted rather than unchanged
+	expected := []string{"  x", "+ b", "- ", "- a", "  y"}
+
+	if strings.Join(actual, "\n") != strings.Join(expected, "\n") {
+		failOutput(t, actual, expected)
+	}
+
+	for which, seq := range map[int][]string{1: a, 2: b} {
+		restored, err := difflibgo.Restore(actual, which)
+		if err != nil || !reflect.DeepEqual(restored, seq) {
+			t.Fatalf("expected to restore %q, got %q (%v)", seq, restored, err)
+		}
+	}
+}
+
+func TestDifferCompareWordDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []difflibgo.Option
+		a        []string
+		b        []string
+		expected []string
+	}{
+		{
+			name: "prose",
+			a:    []string{"description the quick brown fox jumps over the lazy dog"},
+			b:    []string{"description the quick red fox leaps over the lazy dogs"},
+			expected: []string{
+				"- description the quick brown fox jumps over the lazy dog",
+				"?                       ^^^^^     ^^^^^               ^^^\n",
+				"+ description the quick red fox leaps over the lazy dogs",
+				"?                       ^^^     ^^^^^               ^^^^\n",
+			},
+		},
+		{
+			name: "punctuation",
+			a:    []string{"interface Gi0/1.100", "hostname r1"},
+			b:    []string{"interface Gi0/1.200", "hostname r1"},
+			expected: []string{
+				"- interface Gi0/1.100",
+				"?                 ^^^\n",
+				"+ interface Gi0/1.200",
+				"?                 ^^^\n",
+				"  hostname r1",
+			},
+		},
+		{
+			name: "display-width",
+			opts: []difflibgo.Option{difflibgo.WithDisplayWidth(true)},
+			a:    []string{"banner motd ^C 日本語 ok ^C"},
+			b:    []string{"banner motd ^C 日本 ok ^C"},
+			expected: []string{
+				"- banner motd ^C 日本語 ok ^C",
+				"?                ^^^^^^\n",
+				"+ banner motd ^C 日本 ok ^C",
+				"?                ^^^^\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				opts := append([]difflibgo.Option{difflibgo.WithWordDiff(true)}, testCase.opts...)
+
+				actual := difflibgo.NewDiffer(opts...).Compare(testCase.a, testCase.b)
+
+				if strings.Join(actual, "\n") != strings.Join(testCase.expected, "\n") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestNdiff(t *testing.T) {
+	// expected output is from python's difflib.ndiff
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		expected []string
+	}{
+		{
+			name: "python-docs-example",
+			a:    []string{"one\n", "two\n", "three\n"},
+			b:    []string{"ore\n", "tree\n", "emu\n"},
+			expected: []string{
+				"- one\n",
+				"?  ^\n",
+				"+ ore\n",
+				"?  ^\n",
+				"- two\n",
+				"- three\n",
+				"?  -\n",
+				"+ tree\n",
+				"+ emu\n",
+			},
+		},
+		{
+			name: "character-junk",
+			a:    []string{"a  b\tc\n"},
+			b:    []string{"a b\tcd\n"},
+			expected: []string{
+				"- a  b\tc\n",
+				"?   -\n",
+				"+ a b\tcd\n",
+				"?    \t +\n",
+			},
+		},
+		{
+			name: "separator-whitespace",
+			a:    []string{"x\x1cyz\n"},
+			b:    []string{"x\x1cyw\n"},
+			expected: []string{
+				"- x\x1cyz\n",
+				"?  \x1c ^\n",
+				"+ x\x1cyw\n",
+				"?  \x1c ^\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.Ndiff(testCase.a, testCase.b)
+
+				if strings.Join(actual, "") != strings.Join(testCase.expected, "") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestDifferCompareUnicode(t *testing.T) {
+	cases := []struct {
+		name     string
+		opts     []difflibgo.Option
+		a        []string
+		b        []string
+		expected []string
+	}{
+		{
+			// same as python, one marker per code point
+			name: "runes",
+			a:    []string{"description 日本語の説明"},
+			b:    []string{"description 日本人の説明"},
+			expected: []string{
+				"- description 日本語の説明",
+				"?               ^\n",
+				"+ description 日本人の説明",
+				"?               ^\n",
+			},
+		},
+		{
+			name: "display-width",
+			opts: []difflibgo.Option{difflibgo.WithDisplayWidth(true)},
+			a:    []string{"description 日本語の説明"},
+			b:    []string{"description 日本人の説明"},
+			expected: []string{
+				"- description 日本語の説明",
+				"?                 ^^\n",
+				"+ description 日本人の説明",
+				"?                 ^^\n",
+			},
+		},
+		{
+			name: "accented-runes",
+			a:    []string{"hostname cafe\u0301-01"},
+			b:    []string{"hostname cafe-01"},
+			expected: []string{
+				"- hostname cafe\u0301-01",
+				"?              -\n",
+				"+ hostname cafe-01",
+			},
+		},
+		{
+			name: "accented-grapheme-clusters",
+			opts: []difflibgo.Option{difflibgo.WithGraphemeClusters(true)},
+			a:    []string{"hostname cafe\u0301-01"},
+			b:    []string{"hostname cafe-01"},
+			expected: []string{
+				"- hostname cafe\u0301-01",
+				"?             ^\n",
+				"+ hostname cafe-01",
+				"?             ^\n",
+			},
+		},
+		{
+			name: "accented-grapheme-clusters-display-width",
+			opts: []difflibgo.Option{
+				difflibgo.WithGraphemeClusters(true),
+				difflibgo.WithDisplayWidth(true),
+			},
+			a: []string{"if 👍\U0001F3FD cafe\u0301-01"},
+			b: []string{"if 👍 cafe-01"},
+			expected: []string{
+				"- if 👍\U0001F3FD cafe\u0301-01",
+				"?    ^^    ^\n",
+				"+ if 👍 cafe-01",
+				"?    ^^    ^\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.NewDiffer(testCase.opts...).Compare(testCase.a, testCase.b)
+
+				if strings.Join(actual, "\n") != strings.Join(testCase.expected, "\n") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,71 @@
+package difflibgo
+
+// LineKind is the kind of a DiffLine.
+type LineKind int
+
+const (
+	// LineEqual is a line common to both sequences.
+	LineEqual LineKind = iota
+	// LineDelete is a line unique to sequence a.
+	LineDelete
+	// LineInsert is a line unique to sequence b.
+	LineInsert
+	// LineHint is a "?" guide line -- it marks up the intraline differences of the LineDelete or
+	// LineInsert line before it, and is not present in either sequence.
+	LineHint
+	// LineIgnored is a line unique to one of the sequences, LineA or LineB is set to say which,
+	// whose insertion or deletion is ignored -- see WithIgnoreBlankLines and WithIgnoreMatching.
+	// It renders just like a LineDelete or LineInsert line, so Restore still works.
+	LineIgnored
+)
+
+// Span is an intraline change within the Text of a DiffLine -- Text[Start:End] (byte offsets) was
+// replaced (OpReplace), deleted (OpDelete) or inserted (OpInsert).
+type Span struct {
+	Tag   byte
+	Start int
+	End   int
+}
+
+// DiffLine is a single line of Differ output. LineA and LineB are the (one based) line numbers of
+// the line in sequence a and b, a line number is zero if the line is not in that sequence -- for
+// hint lines, the line numbers are those of the line being marked up. Spans are only set for
+// lines that were paired up with a similar line, and describe what changed between the two.
+type DiffLine struct {
+	Kind  LineKind
+	Text  string
+	LineA int
+	LineB int
+	Spans []Span
+}
+
+// String renders the line the way python's difflib.Differ does -- the text prefixed with its
+// two character code ("- ", "+ ", "  " or "? ").
+func (l DiffLine) String() string {
+	switch l.Kind {
+	case LineDelete:
+		return diffSubtraction + l.Text
+	case LineInsert:
+		return diffAddition + l.Text
+	case LineHint:
+		return diffUnknown + l.Text + "\n"
+	case LineIgnored:
+		if l.LineA != 0 {
+			return diffSubtraction + l.Text
+		}
+
+		return diffAddition + l.Text
+	default:
+		return diffEqual + l.Text
+	}
+}
+
+func renderDiffLines(diffLines []DiffLine) []string {
+	rendered := make([]string, len(diffLines))
+
+	for idx, diffLine := range diffLines {
+		rendered[idx] = diffLine.String()
+	}
+
+	return rendered
+}
@@ -0,0 +1,175 @@
+package difflibgo
+
+import "sort"
+
+// histogramMaxChainLength is the number of occurrences (in a) beyond which an element is too
+// common to be a useful anchor; as in git, if a region has such elements it is diffed with
+// Myers' algorithm instead.
+const histogramMaxChainLength = 64
+
+// histogramMaxRegion is the size (the number of elements of a and b) beyond which a region is
+// split on its patience diff anchors before histogram diff proper takes over. Histogram diff
+// scans all of a region for every block it matches, which is quadratic on large regions with
+// many small changes; the unique elements patience diff anchors on are the rarest there are, so
+// they are the blocks histogram diff would mostly pick anyway.
+const histogramMaxRegion = 1 << 14
+
+// histogramIndex holds the positions of the elements of a, built once and shared by all the
+// regions histogram diff splits the sequences into -- the occurrence counts of a region are then
+// found with a binary search rather than by counting the region's elements (into a new map) for
+// every region.
+type histogramIndex struct {
+	// aChains[i] are the (sorted) positions in a of a[i]
+	aChains [][]int
+	// bChains[j] are the (sorted) positions in a of b[j], nil if b[j] is not in a
+	bChains [][]int
+}
+
+func newHistogramIndex[T comparable](a, b []T) *histogramIndex {
+	positions := map[T][]int{}
+
+	for i, elt := range a {
+		positions[elt] = append(positions[elt], i)
+	}
+
+	index := &histogramIndex{aChains: make([][]int, len(a)), bChains: make([][]int, len(b))}
+
+	for i, elt := range a {
+		index.aChains[i] = positions[elt]
+	}
+
+	for j, elt := range b {
+		index.bChains[j] = positions[elt]
+	}
+
+	return index
+}
+
+// regionChain returns the positions of chain in the a side of r.
+func regionChain(chain []int, r matchRange) []int {
+	return chain[sort.SearchInts(chain, r.aLo):sort.SearchInts(chain, r.aHi)]
+}
+
+// rarest returns the lower of count and the occurrence count in r of the element of a at i.
+func (h *histogramIndex) rarest(count, i int, r matchRange) int {
+	// the element itself is in r, so an element unique to a occurs once in r too
+	if count == 1 || len(h.aChains[i]) == 1 {
+		return 1
+	}
+
+	return min(count, len(regionChain(h.aChains[i], r)))
+}
+
+// histogramLongestMatch returns the matching block of a[r.aLo:r.aHi] and b[r.bLo:r.bHi] with the
+// lowest "occurrence count" (the number of times its rarest element occurs in a[r.aLo:r.aHi]),
+// preferring the longest block of those. ok is false if the region has elements too common to be
+// anchors.
+func histogramLongestMatch[T comparable](
+	a, b []T,
+	r matchRange,
+	index *histogramIndex,
+) (best Match, ok bool) {
+	for i := r.aLo; i < r.aHi; i++ {
+		// an element can only be too common in the region if it is too common in all of a
+		if len(index.aChains[i]) > histogramMaxChainLength &&
+			len(regionChain(index.aChains[i], r)) > histogramMaxChainLength {
+			return Match{}, false
+		}
+	}
+
+	bestCount := histogramMaxChainLength + 1
+
+	for j := r.bLo; j < r.bHi; {
+		next := j + 1
+
+		occurrence := regionChain(index.bChains[j], r)
+		if len(occurrence) == 0 || len(occurrence) > bestCount {
+			j = next
+
+			continue
+		}
+
+		for _, i := range occurrence {
+			aLo, aHi, bLo, bHi := i, i+1, j, j+1
+			count := len(occurrence)
+
+			for aLo > r.aLo && bLo > r.bLo && a[aLo-1] == b[bLo-1] {
+				aLo, bLo = aLo-1, bLo-1
+				count = index.rarest(count, aLo, r)
+			}
+
+			for aHi < r.aHi && bHi < r.bHi && a[aHi] == b[bHi] {
+				count = index.rarest(count, aHi, r)
+				aHi, bHi = aHi+1, bHi+1
+			}
+
+			// no need to look for matches starting within this one
+			next = max(next, bHi)
+
+			if best.Size < aHi-aLo || count < bestCount {
+				best, bestCount = Match{A: aLo, B: bLo, Size: aHi - aLo}, count
+			}
+		}
+
+		j = next
+	}
+
+	return best, true
+}
+
+// histogramMatchingBlocks returns the matching blocks of a and b found with histogram diff,
+// sorted by A and B. Regions larger than histogramMaxRegion are split on their patience diff
+// anchors first.
+func histogramMatchingBlocks[T comparable](a, b []T) []Match {
+	fallback := newMyers(a, b)
+	index := newHistogramIndex(a, b)
+
+	var matched []Match
+
+	pending := []matchRange{{0, len(a), 0, len(b)}}
+
+	for len(pending) > 0 {
+		var r matchRange
+
+		r, pending = pending[len(pending)-1], pending[:len(pending)-1]
+		r, matched = trimCommon(a, b, r, matched)
+
+		if r.aLo == r.aHi || r.bLo == r.bHi {
+			continue
+		}
+
+		if r.aHi-r.aLo+r.bHi-r.bLo > histogramMaxRegion {
+			if anchors := patienceAnchors(a, b, r); len(anchors) > 0 {
+				pending = appendAnchored(pending, r, anchors)
+				matched = append(matched, anchors...)
+
+				continue
+			}
+		}
+
+		best, ok := histogramLongestMatch(a, b, r, index)
+		if !ok {
+			fallback.compare(r.aLo, r.aHi, r.bLo, r.bHi)
+
+			continue
+		}
+
+		if best.Size == 0 {
+			// nothing in common
+			continue
+		}
+
+		matched = append(matched, best)
+		pending = append(
+			pending,
+			matchRange{r.aLo, best.A, r.bLo, best.B},
+			matchRange{best.A + best.Size, r.aHi, best.B + best.Size, r.bHi},
+		)
+	}
+
+	matched = append(matched, fallback.matched...)
+
+	sortMatches(matched)
+
+	return matched
+}
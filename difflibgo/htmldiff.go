@@ -0,0 +1,443 @@
+package difflibgo
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// these templates (and the legend/styles) are straight from python's difflib so that HTMLDiff
+// output matches it; the "%(name)s" placeholders are filled in with a strings.Replacer.
+const (
+	htmlFileTemplate = `
+<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"
+          "http://www.w3.org/TR/xhtml1/DTD/xhtml1-transitional.dtd">
+
+<html>
+
+<head>
+    <meta http-equiv="Content-Type"
+          content="text/html; charset=%(charset)s" />
+    <title></title>
+    <style type="text/css">%(styles)s
+    </style>
+</head>
+
+<body>
+    %(table)s%(legend)s
+</body>
+
+</html>`
+
+	htmlStyles = `
+        table.diff {font-family:Courier; border:medium;}
+        .diff_header {background-color:#e0e0e0}
+        td.diff_header {text-align:right}
+        .diff_next {background-color:#c0c0c0}
+        .diff_add {background-color:#aaffaa}
+        .diff_chg {background-color:#ffff77}
+        .diff_sub {background-color:#ffaaaa}`
+
+	htmlTableTemplate = `
+    <table class="diff" id="difflib_chg_%(prefix)s_top"
+           cellspacing="0" cellpadding="0" rules="groups" >
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        %(header_row)s
+        <tbody>
+%(data_rows)s        </tbody>
+    </table>`
+
+	htmlLegend = `
+    <table class="diff" summary="Legends">
+        <tr> <th colspan="2"> Legends </th> </tr>
+        <tr> <td> <table border="" summary="Colors">
+                      <tr><th> Colors </th> </tr>
+                      <tr><td class="diff_add">&nbsp;Added&nbsp;</td></tr>
+                      <tr><td class="diff_chg">Changed</td> </tr>
+                      <tr><td class="diff_sub">Deleted</td> </tr>
+                  </table></td>
+             <td> <table border="" summary="Links">
+                      <tr><th colspan="2"> Links </th> </tr>
+                      <tr><td>(f)irst change</td> </tr>
+                      <tr><td>(n)ext change</td> </tr>
+                      <tr><td>(t)op</td> </tr>
+                  </table></td> </tr>
+    </table>`
+)
+
+// HTMLDiff is a port of python's difflib.HtmlDiff -- it produces an html table (or a complete
+// html file containing the table) showing a side by side, line by line comparison of text with
+// inter-line and intra-line change highlights. The table can be generated in either full or
+// contextual difference mode. Anchor prefixes are unique per HTMLDiff, so use the same HTMLDiff
+// if you want to put several tables on one page.
+type HTMLDiff struct {
+	tabSize    int
+	wrapColumn int
+	differ     *Differ
+
+	tableCount int
+	prefix     [2]string
+}
+
+// NewHTMLDiff returns a new HTMLDiff; see WithTabSize and WithWrapColumn for the options that
+// control tab expansion and line wrapping. WithLineJunk and WithCharJunk are passed on to the
+// Differ used to compare the lines, as in python the charjunk function defaults to
+// IsCharacterJunk.
+func NewHTMLDiff(opts ...Option) *HTMLDiff {
+	o := newOptions(append([]Option{WithCharJunk(IsCharacterJunk)}, opts...)...)
+
+	// the table shows the lines of both sides, so the options that make differing lines compare
+	// equal don't apply, and the markup has nothing to do with terminal columns
+	o.displayWidth = false
+	o.normalizeLineEndings = false
+	o.ignoreSpaceChange, o.ignoreAllSpace, o.ignoreBlankLines = false, false, false
+	o.lineKey, o.masks, o.ignorePatterns = nil, nil, nil
+
+	return &HTMLDiff{
+		tabSize:    o.tabSize,
+		wrapColumn: o.wrapColumn,
+		differ:     newDiffer(o),
+	}
+}
+
+// MakeFile returns a complete html file of the side by side comparison of fromLines and toLines
+// with change highlights. fromDesc and toDesc are the column headers for the two sides. If
+// context is true only the changes plus numLines lines of context are shown, otherwise numLines
+// controls how many lines before a change the "next" links jump to.
+func (h *HTMLDiff) MakeFile(
+	fromLines, toLines []string,
+	fromDesc, toDesc string,
+	context bool,
+	numLines int,
+) string {
+	return strings.NewReplacer(
+		"%(styles)s", htmlStyles,
+		"%(legend)s", htmlLegend,
+		"%(table)s", h.MakeTable(fromLines, toLines, fromDesc, toDesc, context, numLines),
+		"%(charset)s", "utf-8",
+	).Replace(htmlFileTemplate)
+}
+
+func expandTabs(line string, tabSize int) string {
+	var expanded strings.Builder
+
+	column := 0
+
+	for _, r := range line {
+		switch r {
+		case '\t':
+			if tabSize > 0 {
+				spaces := tabSize - column%tabSize
+				expanded.WriteString(strings.Repeat(" ", spaces))
+				column += spaces
+			}
+		case '\n', '\r':
+			expanded.WriteRune(r)
+
+			column = 0
+		default:
+			expanded.WriteRune(r)
+
+			column++
+		}
+	}
+
+	return expanded.String()
+}
+
+// tabNewlineReplace expands tabs and removes trailing newlines. Instead of tab characters being
+// replaced by spaces they are replaced by tab characters (one per column) -- this is done so the
+// differ can spot changes where tabs are replaced by spaces and vice versa. At the end of the
+// html generation the tabs are replaced with non-breaking spaces.
+func (h *HTMLDiff) tabNewlineReplace(lines []string) []string {
+	replaced := make([]string, len(lines))
+
+	for idx, line := range lines {
+		// hide real spaces, expand tabs, turn the expanded spaces back into tabs, then bring
+		// back the real spaces
+		line = strings.ReplaceAll(line, " ", "\x00")
+		line = expandTabs(line, h.tabSize)
+		line = strings.ReplaceAll(line, " ", "\t")
+		line = strings.ReplaceAll(line, "\x00", " ")
+
+		replaced[idx] = strings.TrimRight(line, "\n")
+	}
+
+	return replaced
+}
+
+// splitLine wraps line at the wrap column, taking care to close and re-open any change markers
+// the wrap point falls within.
+func (h *HTMLDiff) splitLine(lines []mdiffLine, line mdiffLine) []mdiffLine {
+	// if blank line or context separator, just add it to the output list
+	if line.lineNum == "" {
+		return append(lines, line)
+	}
+
+	text := []rune(line.text)
+	size := len(text)
+
+	if size <= h.wrapColumn ||
+		size-strings.Count(line.text, mdiffMarkStart)*3 <= h.wrapColumn {
+		return append(lines, line)
+	}
+
+	// scan text looking for the wrap point, keeping track if the wrap point is inside markers
+	i, n := 0, 0
+
+	var mark string
+
+	for n < h.wrapColumn && i < size {
+		switch string(text[i]) {
+		case mdiffMarkStart:
+			i++
+			mark = string(text[i])
+			i++
+		case mdiffMarkEnd:
+			i++
+			mark = ""
+		default:
+			i++
+			n++
+		}
+	}
+
+	line1, line2 := string(text[:i]), string(text[i:])
+
+	// if wrap point is inside markers, place end marker at end of first line and start marker
+	// at beginning of second line because each line will have its own table tag markup around it
+	if mark != "" {
+		line1 += mdiffMarkEnd
+		line2 = mdiffMarkStart + mark + line2
+	}
+
+	lines = append(lines, mdiffLine{lineNum: line.lineNum, text: line1})
+
+	return h.splitLine(lines, mdiffLine{lineNum: ">", text: line2})
+}
+
+func (h *HTMLDiff) wrapLines(rows []mdiffRow) []mdiffRow {
+	var wrapped []mdiffRow
+
+	for _, row := range rows {
+		if row.separator {
+			wrapped = append(wrapped, row)
+
+			continue
+		}
+
+		fromLines := h.splitLine(nil, *row.from)
+		toLines := h.splitLine(nil, *row.to)
+
+		// yield from/to line in pairs inserting blank lines as necessary when one side has more
+		// wrapped lines
+		for len(fromLines) > 0 || len(toLines) > 0 {
+			from, to := &mdiffLine{text: " "}, &mdiffLine{text: " "}
+
+			if len(fromLines) > 0 {
+				from = &fromLines[0]
+				fromLines = fromLines[1:]
+			}
+
+			if len(toLines) > 0 {
+				to = &toLines[0]
+				toLines = toLines[1:]
+			}
+
+			wrapped = append(wrapped, mdiffRow{from: from, to: to, changed: row.changed})
+		}
+	}
+
+	return wrapped
+}
+
+func (h *HTMLDiff) formatLine(side int, line *mdiffLine) string {
+	var id string
+
+	if line.hasID() {
+		id = fmt.Sprintf(` id="%s%s"`, h.prefix[side], line.lineNum)
+	}
+
+	// replace those things that would get confused with html symbols, and make spaces
+	// non-breakable so they don't get compressed or line wrapped
+	text := strings.NewReplacer("&", "&amp;", ">", "&gt;", "<", "&lt;").Replace(line.text)
+	text = strings.TrimRightFunc(strings.ReplaceAll(text, " ", "&nbsp;"), unicode.IsSpace)
+
+	return fmt.Sprintf(
+		`<td class="diff_header"%s>%s</td><td nowrap="nowrap">%s</td>`,
+		id,
+		line.lineNum,
+		text,
+	)
+}
+
+func (h *HTMLDiff) makePrefix() {
+	// generate a unique anchor prefix so multiple tables can exist on the same html page
+	// without conflicts
+	h.prefix = [2]string{
+		"from" + strconv.Itoa(h.tableCount) + "_",
+		"to" + strconv.Itoa(h.tableCount) + "_",
+	}
+
+	h.tableCount++
+}
+
+// convertFlags makes the "next" anchors and links for the middle columns of the table.
+func (h *HTMLDiff) convertFlags(
+	fromList, toList []string,
+	rows []mdiffRow,
+	context bool,
+	numLines int,
+) (fromOut, toOut []string, rowsOut []mdiffRow, nextHref, nextID []string) {
+	// all anchor names will be generated using the unique "to" prefix
+	toPrefix := h.prefix[1]
+
+	nextID = make([]string, len(rows))
+	nextHref = make([]string, len(rows))
+
+	numChg, inChange, last := 0, false, 0
+
+	for i, row := range rows {
+		if !row.changed {
+			inChange = false
+
+			continue
+		}
+
+		if inChange {
+			continue
+		}
+
+		inChange = true
+		last = i
+
+		// at the beginning of a change, drop an anchor a few lines (the context lines) before
+		// the change for the previous link, and drop a link to the next change
+		nextID[max(0, i-numLines)] = fmt.Sprintf(` id="difflib_chg_%s_%d"`, toPrefix, numChg)
+		numChg++
+		nextHref[last] = fmt.Sprintf(`<a href="#difflib_chg_%s_%d">n</a>`, toPrefix, numChg)
+	}
+
+	// check for cases where there is no content to avoid exceptions
+	if len(rows) == 0 {
+		rows = []mdiffRow{{}}
+		nextID = []string{""}
+		nextHref = []string{""}
+		last = 0
+
+		if context {
+			fromList = []string{"<td></td><td>&nbsp;No Differences Found&nbsp;</td>"}
+		} else {
+			fromList = []string{"<td></td><td>&nbsp;Empty File&nbsp;</td>"}
+		}
+
+		toList = fromList
+	}
+
+	// if not a change on first line, drop a link
+	if !rows[0].changed {
+		nextHref[0] = fmt.Sprintf(`<a href="#difflib_chg_%s_0">f</a>`, toPrefix)
+	}
+
+	// redo the last link to link to the top
+	nextHref[last] = fmt.Sprintf(`<a href="#difflib_chg_%s_top">t</a>`, toPrefix)
+
+	return fromList, toList, rows, nextHref, nextID
+}
+
+// MakeTable returns an html table of the side by side comparison of fromLines and toLines with
+// change highlights; the arguments are the same as MakeFile.
+func (h *HTMLDiff) MakeTable(
+	fromLines, toLines []string,
+	fromDesc, toDesc string,
+	context bool,
+	numLines int,
+) string {
+	// make unique anchor prefixes so that multiple tables may exist on the same page
+	h.makePrefix()
+
+	// change tabs to spaces before it gets more difficult after we insert markup
+	fromLines, toLines = h.tabNewlineReplace(fromLines), h.tabNewlineReplace(toLines)
+
+	contextLines := -1
+	if context {
+		contextLines = numLines
+	}
+
+	rows := mdiff(h.differ, fromLines, toLines, contextLines)
+
+	if h.wrapColumn > 0 {
+		rows = h.wrapLines(rows)
+	}
+
+	fromList, toList := make([]string, len(rows)), make([]string, len(rows))
+
+	for idx, row := range rows {
+		if row.separator {
+			continue
+		}
+
+		fromList[idx] = h.formatLine(0, row.from)
+		toList[idx] = h.formatLine(1, row.to)
+	}
+
+	fromList, toList, rows, nextHref, nextID := h.convertFlags(
+		fromList,
+		toList,
+		rows,
+		context,
+		numLines,
+	)
+
+	var dataRows strings.Builder
+
+	for i, row := range rows {
+		if row.separator {
+			// mdiff yields separators on the first line too, skip those
+			if i > 0 {
+				dataRows.WriteString("        </tbody>        \n        <tbody>\n")
+			}
+
+			continue
+		}
+
+		fmt.Fprintf(
+			&dataRows,
+			"            <tr><td class=\"diff_next\"%s>%s</td>%s"+
+				"<td class=\"diff_next\">%s</td>%s</tr>\n",
+			nextID[i],
+			nextHref[i],
+			fromList[i],
+			nextHref[i],
+			toList[i],
+		)
+	}
+
+	var headerRow string
+
+	if fromDesc != "" || toDesc != "" {
+		headerRow = fmt.Sprintf(
+			"<thead><tr>%s%s%s%s</tr></thead>",
+			`<th class="diff_next"><br /></th>`,
+			fmt.Sprintf(`<th colspan="2" class="diff_header">%s</th>`, fromDesc),
+			`<th class="diff_next"><br /></th>`,
+			fmt.Sprintf(`<th colspan="2" class="diff_header">%s</th>`, toDesc),
+		)
+	}
+
+	table := strings.NewReplacer(
+		"%(data_rows)s", dataRows.String(),
+		"%(header_row)s", headerRow,
+		"%(prefix)s", h.prefix[1],
+	).Replace(htmlTableTemplate)
+
+	return strings.NewReplacer(
+		mdiffMarkStart+"+", `<span class="diff_add">`,
+		mdiffMarkStart+"-", `<span class="diff_sub">`,
+		mdiffMarkStart+"^", `<span class="diff_chg">`,
+		mdiffMarkEnd, "</span>",
+		"\t", "&nbsp;",
+	).Replace(table)
+}
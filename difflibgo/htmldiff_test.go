@@ -0,0 +1,137 @@
+package difflibgo_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestHTMLDiffMakeTable(t *testing.T) {
+	cases := []struct {
+		name       string
+		a          []string
+		b          []string
+		fromDesc   string
+		toDesc     string
+		context    bool
+		numLines   int
+		wrapColumn int
+		expected   string
+	}{
+		{
+			name:       "full",
+			a:          []string{"one", "two", "three"},
+			b:          []string{"one", "tw0", "three", "four"},
+			fromDesc:   "a",
+			toDesc:     "b",
+			context:    false,
+			numLines:   5,
+			wrapColumn: 0,
+			expected: `
+    <table class="diff" id="difflib_chg_to0__top"
+           cellspacing="0" cellpadding="0" rules="groups" >
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        <thead><tr><th class="diff_next"><br /></th><th colspan="2" class="diff_header">a</th><th class="diff_next"><br /></th><th colspan="2" class="diff_header">b</th></tr></thead>
+        <tbody>
+            <tr><td class="diff_next" id="difflib_chg_to0__1"><a href="#difflib_chg_to0__0">f</a></td><td class="diff_header" id="from0_1">1</td><td nowrap="nowrap">one</td><td class="diff_next"><a href="#difflib_chg_to0__0">f</a></td><td class="diff_header" id="to0_1">1</td><td nowrap="nowrap">one</td></tr>
+            <tr><td class="diff_next"><a href="#difflib_chg_to0__1">n</a></td><td class="diff_header" id="from0_2">2</td><td nowrap="nowrap"><span class="diff_sub">two</span></td><td class="diff_next"><a href="#difflib_chg_to0__1">n</a></td><td class="diff_header" id="to0_2">2</td><td nowrap="nowrap"><span class="diff_add">tw0</span></td></tr>
+            <tr><td class="diff_next"></td><td class="diff_header" id="from0_3">3</td><td nowrap="nowrap">three</td><td class="diff_next"></td><td class="diff_header" id="to0_3">3</td><td nowrap="nowrap">three</td></tr>
+            <tr><td class="diff_next"><a href="#difflib_chg_to0__top">t</a></td><td class="diff_header"></td><td nowrap="nowrap"></td><td class="diff_next"><a href="#difflib_chg_to0__top">t</a></td><td class="diff_header" id="to0_4">4</td><td nowrap="nowrap"><span class="diff_add">four</span></td></tr>
+        </tbody>
+    </table>`,
+		},
+		{
+			name:       "context-no-differences",
+			a:          []string{"one", "two"},
+			b:          []string{"one", "two"},
+			fromDesc:   "",
+			toDesc:     "",
+			context:    true,
+			numLines:   5,
+			wrapColumn: 0,
+			expected: `
+    <table class="diff" id="difflib_chg_to0__top"
+           cellspacing="0" cellpadding="0" rules="groups" >
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        
+        <tbody>
+            <tr><td class="diff_next"><a href="#difflib_chg_to0__top">t</a></td><td></td><td>&nbsp;No Differences Found&nbsp;</td><td class="diff_next"><a href="#difflib_chg_to0__top">t</a></td><td></td><td>&nbsp;No Differences Found&nbsp;</td></tr>
+        </tbody>
+    </table>`,
+		},
+		{
+			name:       "intraline-tabs-and-wrapping",
+			a:          []string{"abc\tdefghij"},
+			b:          []string{"abc\tdefXhij"},
+			fromDesc:   "",
+			toDesc:     "",
+			context:    false,
+			numLines:   5,
+			wrapColumn: 6,
+			expected: `
+    <table class="diff" id="difflib_chg_to0__top"
+           cellspacing="0" cellpadding="0" rules="groups" >
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        <colgroup></colgroup> <colgroup></colgroup> <colgroup></colgroup>
+        
+        <tbody>
+            <tr><td class="diff_next" id="difflib_chg_to0__0"><a href="#difflib_chg_to0__top">t</a></td><td class="diff_header" id="from0_1">1</td><td nowrap="nowrap">abc</td><td class="diff_next"><a href="#difflib_chg_to0__top">t</a></td><td class="diff_header" id="to0_1">1</td><td nowrap="nowrap">abc</td></tr>
+            <tr><td class="diff_next"></td><td class="diff_header">></td><td nowrap="nowrap">&nbsp;&nbsp;def<span class="diff_chg">g</span></td><td class="diff_next"></td><td class="diff_header">></td><td nowrap="nowrap">&nbsp;&nbsp;def<span class="diff_chg">X</span></td></tr>
+            <tr><td class="diff_next"></td><td class="diff_header">></td><td nowrap="nowrap"><span class="diff_chg"></span>hij</td><td class="diff_next"></td><td class="diff_header">></td><td nowrap="nowrap"><span class="diff_chg"></span>hij</td></tr>
+        </tbody>
+    </table>`,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				h := difflibgo.NewHTMLDiff(difflibgo.WithWrapColumn(testCase.wrapColumn))
+
+				actual := h.MakeTable(
+					testCase.a,
+					testCase.b,
+					testCase.fromDesc,
+					testCase.toDesc,
+					testCase.context,
+					testCase.numLines,
+				)
+
+				if actual != testCase.expected {
+					failOutput(
+						t,
+						strings.Split(actual, "\n"),
+						strings.Split(testCase.expected, "\n"),
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestHTMLDiffMakeFile(t *testing.T) {
+	h := difflibgo.NewHTMLDiff()
+
+	actual := h.MakeFile([]string{"one"}, []string{"two"}, "a", "b", false, 5)
+
+	for _, expected := range []string{
+		`<!DOCTYPE html PUBLIC "-//W3C//DTD XHTML 1.0 Transitional//EN"`,
+		`content="text/html; charset=utf-8" />`,
+		`.diff_add {background-color:#aaffaa}`,
+		`<table class="diff" id="difflib_chg_to0__top"`,
+		`<table class="diff" summary="Legends">`,
+	} {
+		if !strings.Contains(actual, expected) {
+			t.Fatalf("expected html file to contain %q, but it did not:\n%s", expected, actual)
+		}
+	}
+
+	// each table gets a unique prefix so they can share a page
+	if !strings.Contains(h.MakeTable(nil, nil, "", "", false, 5), "difflib_chg_to1__top") {
+		t.Fatal("expected second table to have a unique anchor prefix")
+	}
+}
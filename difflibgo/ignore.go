@@ -0,0 +1,122 @@
+package difflibgo
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// spaceChangeKey is the comparison key of a line for WithIgnoreSpaceChange -- every run of
+// whitespace is squashed to a single space and trailing whitespace (line terminators included)
+// is dropped, so "a  b\n" and "a b" are the same but "a b" and "ab" are not.
+func spaceChangeKey(line string) string {
+	var key strings.Builder
+
+	space := false
+
+	for _, c := range line {
+		if unicode.IsSpace(c) {
+			space = true
+
+			continue
+		}
+
+		if space {
+			key.WriteByte(' ')
+
+			space = false
+		}
+
+		key.WriteRune(c)
+	}
+
+	return key.String()
+}
+
+// allSpaceKey is the comparison key of a line for WithIgnoreAllSpace, the line without any
+// whitespace at all.
+func allSpaceKey(line string) string {
+	return strings.Map(func(c rune) rune {
+		if unicode.IsSpace(c) {
+			return -1
+		}
+
+		return c
+	}, line)
+}
+
+// isBlankLine returns true if line is empty bar its terminator, or, if there is a key function,
+// if its key is.
+func isBlankLine(line string, key func(string) string) bool {
+	if key != nil {
+		line = key(line)
+	}
+
+	return strings.TrimRight(line, "\r\n") == ""
+}
+
+// ignoredKey is the key of all the lines matching the WithIgnoreMatching patterns.
+const ignoredKey = "\x00ignored\x00"
+
+func matchesAny(patterns []*regexp.Regexp, line string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(line) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// mask replaces the matches of a regular expression with a placeholder, see WithMaskMatching.
+type mask struct {
+	pattern     *regexp.Regexp
+	placeholder string
+}
+
+// maskKey returns the key function that applies masks to a line.
+func maskKey(masks []mask) func(string) string {
+	return func(line string) string {
+		for _, m := range masks {
+			line = m.pattern.ReplaceAllLiteralString(line, m.placeholder)
+		}
+
+		return line
+	}
+}
+
+// isIgnoredChange returns true if the (non equal) opCode only inserts or deletes lines that are
+// ignored -- blank lines for WithIgnoreBlankLines, lines matching WithIgnoreMatching patterns.
+func isIgnoredChange(opCode OpCode, a, b []string, ignored func(string) bool) bool {
+	for _, line := range a[opCode.SeqALo:opCode.SeqAHi] {
+		if !ignored(line) {
+			return false
+		}
+	}
+
+	for _, line := range b[opCode.SeqBLo:opCode.SeqBHi] {
+		if !ignored(line) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// dropIgnoredGroups drops the groups that only change ignored lines. As with diff -B and -I, an
+// ignored change in a group that has other changes too is still shown.
+func dropIgnoredGroups(groups [][]OpCode, a, b []string, ignored func(string) bool) [][]OpCode {
+	var kept [][]OpCode
+
+	for _, group := range groups {
+		for _, opCode := range group {
+			if opCode.Tag != OpEqual && !isIgnoredChange(opCode, a, b, ignored) {
+				kept = append(kept, group)
+
+				break
+			}
+		}
+	}
+
+	return kept
+}
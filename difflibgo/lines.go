@@ -0,0 +1,114 @@
+package difflibgo
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// noNewlineMarker follows a diff line that is the last line of its file but has no trailing
+// newline, as in the output of diff.
+const noNewlineMarker = `\ No newline at end of file`
+
+// isLineBoundary returns true for the characters python's str.splitlines splits on.
+func isLineBoundary(c rune) bool {
+	switch c {
+	case '\n', '\r', '\v', '\f', '\x1c', '\x1d', '\x1e', '\u0085', '\u2028', '\u2029':
+		return true
+	default:
+		return false
+	}
+}
+
+// SplitLines splits s into lines the way python's str.splitlines does -- on "\n", "\r\n" and
+// "\r" as well as the other unicode line boundaries -- keeping the line terminators if keepEnds
+// is true. Unlike strings.Split(s, "\n") there is no empty line at the end of a string that ends
+// with a newline, an empty string has no lines at all, and a "\r\n" terminator is never split in
+// two. With keepEnds the lines of a string that does not end with a newline are exactly those
+// of one that does, bar the missing terminator of the last line.
+func SplitLines(s string, keepEnds bool) []string {
+	var lines []string
+
+	start := 0
+
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRuneInString(s[i:])
+
+		end := i
+		i += size
+
+		if !isLineBoundary(c) {
+			continue
+		}
+
+		if c == '\r' && i < len(s) && s[i] == '\n' {
+			i++
+		}
+
+		if keepEnds {
+			end = i
+		}
+
+		lines = append(lines, s[start:end])
+		start = i
+	}
+
+	if start < len(s) {
+		lines = append(lines, s[start:])
+	}
+
+	return lines
+}
+
+// normalizeLineEnding is the comparison key of a line for WithNormalizeLineEndings, the line
+// with a "\r\n" terminator replaced by "\n".
+func normalizeLineEnding(line string) string {
+	if strings.HasSuffix(line, "\r\n") {
+		return line[:len(line)-2] + "\n"
+	}
+
+	return line
+}
+
+// hasLineEnding returns true if line ends with a newline ("\n", or "\r\n") -- the other line
+// boundaries SplitLines splits on do not terminate a line as far as diff and patch are concerned,
+// a line ending in a form feed is still missing its newline.
+func hasLineEnding(line string) bool {
+	return strings.HasSuffix(line, "\n")
+}
+
+// hasLineEndings returns true if any line of the seqs ends with a line terminator -- lines without
+// any terminators (as from strings.Split) are never missing their newline.
+func hasLineEndings(seqs ...[]string) bool {
+	for _, seq := range seqs {
+		for _, line := range seq {
+			if hasLineEnding(line) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// appendHunkLines appends the lines seq[lo:hi] with prefix to hunkLines. If the lines being
+// diffed keep their terminators (see hasLineEndings) and the last line of seq is among them but
+// has none, it is terminated with the line terminator and followed by the "\ No newline at end of
+// file" marker like diff does -- unless the line terminator is "", then the lines are taken as is.
+func appendHunkLines(
+	hunkLines []string,
+	prefix string,
+	seq []string,
+	lo, hi int,
+	o *options,
+) []string {
+	for _, line := range seq[lo:hi] {
+		hunkLines = append(hunkLines, prefix+line)
+	}
+
+	if hi == len(seq) && lo < hi && o.lineTerm != "" && o.lineEndings && !hasLineEnding(seq[hi-1]) {
+		hunkLines[len(hunkLines)-1] += o.lineTerm
+		hunkLines = append(hunkLines, noNewlineMarker+o.lineTerm)
+	}
+
+	return hunkLines
+}
@@ -0,0 +1,78 @@
+package difflibgo_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestSplitLines(t *testing.T) {
+	cases := []struct {
+		name     string
+		s        string
+		keepEnds bool
+		expected []string
+	}{
+		{
+			name:     "empty",
+			s:        "",
+			expected: nil,
+		},
+		{
+			name:     "trailing-newline",
+			s:        "one\ntwo\n",
+			expected: []string{"one", "two"},
+		},
+		{
+			name:     "no-trailing-newline",
+			s:        "one\ntwo",
+			expected: []string{"one", "two"},
+		},
+		{
+			name:     "keep-ends",
+			s:        "one\ntwo",
+			keepEnds: true,
+			expected: []string{"one\n", "two"},
+		},
+		{
+			name:     "crlf",
+			s:        "one\r\ntwo\r\n",
+			expected: []string{"one", "two"},
+		},
+		{
+			name:     "crlf-keep-ends",
+			s:        "one\r\ntwo\r\n",
+			keepEnds: true,
+			expected: []string{"one\r\n", "two\r\n"},
+		},
+		{
+			name:     "mixed-line-boundaries",
+			s:        "one\rtwo\n\nthree\u2028four\x0c",
+			keepEnds: true,
+			expected: []string{"one\r", "two\n", "\n", "three\u2028", "four\x0c"},
+		},
+		{
+			name:     "blank-lines",
+			s:        "\n\n",
+			expected: []string{"", ""},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.SplitLines(testCase.s, testCase.keepEnds)
+
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf(
+						"actual and expected do not match...\nactual: %q\nexpected: %q",
+						actual,
+						testCase.expected,
+					)
+				}
+			},
+		)
+	}
+}
@@ -0,0 +1,338 @@
+package difflibgo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// the "\x00" and "\x01" markers wrap intraline (or whole line) changes in mdiff text so that we
+// can find them after html escaping the text, the character after "\x00" is the change type.
+const (
+	mdiffMarkStart = "\x00"
+	mdiffMarkEnd   = "\x01"
+)
+
+// mdiffSpanMarkers are the "?" guide line markers of the intraline span tags.
+var mdiffSpanMarkers = map[byte]string{
+	OpReplace: "^",
+	OpDelete:  "-",
+	OpInsert:  "+",
+}
+
+// mdiffLine is one side of a row of side by side output. lineNum is the line number (as a
+// string), "" for a filler line that exists only to line up the other side, or ">" for the
+// continuation of a wrapped line.
+type mdiffLine struct {
+	lineNum string
+	text    string
+}
+
+func (l *mdiffLine) hasID() bool {
+	return l.lineNum != "" && l.lineNum != ">"
+}
+
+// mdiffRow is a row of side by side output; separator rows (nil from and to) mark the gap
+// between context hunks.
+type mdiffRow struct {
+	from      *mdiffLine
+	to        *mdiffLine
+	changed   bool
+	separator bool
+}
+
+// mdiffer is a port of python's difflib._mdiff, it turns Differ output into side by side rows
+// for HTMLDiff. Unlike python, this is not a generator, the rows are simply collected up. Python
+// works on the rendered Differ lines and digs the intraline changes out of the "?" guide lines,
+// we work on the structured lines and their spans instead; a nil pending line is python's "X"
+// end marker.
+type mdiffer struct {
+	diffLines []DiffLine
+	lineNums  [2]int
+	pending   []*DiffLine
+}
+
+func (m *mdiffer) next() *DiffLine {
+	if len(m.diffLines) == 0 {
+		return nil
+	}
+
+	line := &m.diffLines[0]
+	m.diffLines = m.diffLines[1:]
+
+	return line
+}
+
+func (m *mdiffer) pop() *DiffLine {
+	line := m.pending[0]
+	m.pending = m.pending[1:]
+
+	return line
+}
+
+// prefixes returns the first characters of the rendered pending lines, "X" for the end marker.
+func (m *mdiffer) prefixes() string {
+	var prefixes string
+
+	for _, line := range m.pending {
+		if line == nil {
+			prefixes += "X"
+		} else {
+			prefixes += line.String()[:1]
+		}
+	}
+
+	return prefixes
+}
+
+// makeLine pops the next line (and its "?" guide line if formatKey is '?') from the pending lines
+// and returns it with change markers inserted; a formatKey of zero means no markup.
+func (m *mdiffer) makeLine(formatKey byte, side int) *mdiffLine {
+	m.lineNums[side]++
+
+	var text string
+
+	switch formatKey {
+	case 0:
+		text = m.pop().Text
+	case '?':
+		line := m.pop()
+		text = line.Text
+
+		// pop the guide line, its markers are the spans of the line
+		m.pop()
+
+		for i := len(line.Spans) - 1; i >= 0; i-- {
+			begin, end := line.Spans[i].Start, line.Spans[i].End
+
+			text = text[:begin] + mdiffMarkStart + mdiffSpanMarkers[line.Spans[i].Tag] +
+				text[begin:end] + mdiffMarkEnd + text[end:]
+		}
+	default:
+		text = m.pop().Text
+
+		// if line of text is empty, insert a space so there is something to highlight
+		if text == "" {
+			text = " "
+		}
+
+		text = mdiffMarkStart + string(formatKey) + text + mdiffMarkEnd
+	}
+
+	return &mdiffLine{lineNum: strconv.Itoa(m.lineNums[side]), text: text}
+}
+
+// lines yields from/to lines with a change indication, lining up the two sides with filler
+// lines where one side has more changed lines than the other. Either side may be nil if only one
+// side had a line to give.
+func (m *mdiffer) lines() []mdiffRow { //nolint:gocyclo,funlen
+	var rows []mdiffRow
+
+	numBlanksPending, numBlanksToYield := 0, 0
+
+	for {
+		for len(m.pending) < 4 { //nolint:gomnd
+			m.pending = append(m.pending, m.next())
+		}
+
+		prefixes := m.prefixes()
+
+		var fromLine, toLine *mdiffLine
+
+		switch {
+		case strings.HasPrefix(prefixes, "X"):
+			// no more lines, pump out any remaining blank lines so the corresponding
+			// add/delete lines get a matching blank line
+			numBlanksToYield = numBlanksPending
+		case strings.HasPrefix(prefixes, "-?+?"):
+			// simple intraline change
+			rows = append(
+				rows,
+				mdiffRow{from: m.makeLine('?', 0), to: m.makeLine('?', 1), changed: true},
+			)
+
+			continue
+		case strings.HasPrefix(prefixes, "--++"):
+			// in delete block, add block coming: we do NOT want to get caught up on blank
+			// lines yet, just process the delete line
+			numBlanksPending--
+
+			rows = append(rows, mdiffRow{from: m.makeLine('-', 0), changed: true})
+
+			continue
+		case strings.HasPrefix(prefixes, "--?+"),
+			strings.HasPrefix(prefixes, "--+"),
+			strings.HasPrefix(prefixes, "- "):
+			// in delete block and see an intraline change or unchanged line coming: yield the
+			// delete line and then blanks
+			fromLine = m.makeLine('-', 0)
+			numBlanksToYield, numBlanksPending = numBlanksPending-1, 0
+		case strings.HasPrefix(prefixes, "-+?"):
+			// intraline change
+			rows = append(
+				rows,
+				mdiffRow{from: m.makeLine(0, 0), to: m.makeLine('?', 1), changed: true},
+			)
+
+			continue
+		case strings.HasPrefix(prefixes, "-?+"):
+			// intraline change
+			rows = append(
+				rows,
+				mdiffRow{from: m.makeLine('?', 0), to: m.makeLine(0, 1), changed: true},
+			)
+
+			continue
+		case strings.HasPrefix(prefixes, "-"):
+			// delete from line
+			numBlanksPending--
+
+			rows = append(rows, mdiffRow{from: m.makeLine('-', 0), changed: true})
+
+			continue
+		case strings.HasPrefix(prefixes, "+--"):
+			// in add block, delete block coming: we do NOT want to get caught up on blank lines
+			// yet, just process the add line
+			numBlanksPending++
+
+			rows = append(rows, mdiffRow{to: m.makeLine('+', 1), changed: true})
+
+			continue
+		case strings.HasPrefix(prefixes, "+ "), strings.HasPrefix(prefixes, "+-"):
+			// will be leaving an add block: yield blanks then add line
+			toLine = m.makeLine('+', 1)
+			numBlanksToYield, numBlanksPending = numBlanksPending+1, 0
+		case strings.HasPrefix(prefixes, "+"):
+			// inside an add block, yield the add line
+			numBlanksPending++
+
+			rows = append(rows, mdiffRow{to: m.makeLine('+', 1), changed: true})
+
+			continue
+		case strings.HasPrefix(prefixes, " "):
+			// unchanged text, yield it to both sides
+			m.pending = append([]*DiffLine{m.pending[0]}, m.pending...)
+
+			rows = append(rows, mdiffRow{from: m.makeLine(0, 0), to: m.makeLine(0, 1)})
+
+			continue
+		}
+
+		// catch up on the blank lines so when we yield the next from/to pair, they are lined up
+		for ; numBlanksToYield < 0; numBlanksToYield++ {
+			rows = append(rows, mdiffRow{to: &mdiffLine{text: "\n"}, changed: true})
+		}
+
+		for ; numBlanksToYield > 0; numBlanksToYield-- {
+			rows = append(rows, mdiffRow{from: &mdiffLine{text: "\n"}, changed: true})
+		}
+
+		if strings.HasPrefix(prefixes, "X") {
+			return rows
+		}
+
+		rows = append(rows, mdiffRow{from: fromLine, to: toLine, changed: true})
+	}
+}
+
+// linePairs pairs up the from/to lines from lines so that every row has both sides.
+func (m *mdiffer) linePairs() []mdiffRow {
+	type pending struct {
+		line    *mdiffLine
+		changed bool
+	}
+
+	var rows []mdiffRow
+
+	var fromLines, toLines []pending
+
+	for _, row := range m.lines() {
+		if row.from != nil {
+			fromLines = append(fromLines, pending{row.from, row.changed})
+		}
+
+		if row.to != nil {
+			toLines = append(toLines, pending{row.to, row.changed})
+		}
+
+		for len(fromLines) > 0 && len(toLines) > 0 {
+			from, to := fromLines[0], toLines[0]
+			fromLines, toLines = fromLines[1:], toLines[1:]
+
+			rows = append(rows, mdiffRow{
+				from:    from.line,
+				to:      to.line,
+				changed: from.changed || to.changed,
+			})
+		}
+	}
+
+	return rows
+}
+
+// mdiff returns the side by side rows for fromLines/toLines. If context is negative all lines are
+// returned, otherwise only changes with context lines of surrounding context, separated by
+// separator rows.
+func mdiff(d *Differ, fromLines, toLines []string, context int) []mdiffRow {
+	m := &mdiffer{diffLines: d.CompareStructured(fromLines, toLines)}
+
+	pairs := m.linePairs()
+
+	if context < 0 {
+		return pairs
+	}
+
+	var rows []mdiffRow
+
+	context++
+
+	for len(pairs) > 0 {
+		// store lines up until we find a difference, note use of a circular queue because we
+		// only need to keep around what we need for context
+		index, contextLines := 0, make([]mdiffRow, context)
+		foundDiff := false
+
+		for !foundDiff {
+			if len(pairs) == 0 {
+				return rows
+			}
+
+			contextLines[index%context] = pairs[0]
+			foundDiff = pairs[0].changed
+			pairs = pairs[1:]
+			index++
+		}
+
+		// yield lines that we have collected so far, but first yield the separator
+		var linesToWrite int
+
+		if index > context {
+			rows = append(rows, mdiffRow{separator: true})
+			linesToWrite = context
+		} else {
+			linesToWrite = index
+			index = 0
+		}
+
+		for ; linesToWrite > 0; linesToWrite-- {
+			rows = append(rows, contextLines[index%context])
+			index++
+		}
+
+		// now yield the context lines after the change
+		for linesToWrite = context - 1; linesToWrite > 0 && len(pairs) > 0; {
+			pair := pairs[0]
+			pairs = pairs[1:]
+
+			// if another change within the context, extend the context
+			if pair.changed {
+				linesToWrite = context - 1
+			} else {
+				linesToWrite--
+			}
+
+			rows = append(rows, pair)
+		}
+	}
+
+	return rows
+}
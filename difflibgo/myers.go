@@ -0,0 +1,141 @@
+package difflibgo
+
+// myers finds the matching blocks of a and b with Myers' O(ND) difference algorithm, using the
+// linear space refinement from the same paper ("An O(ND) Difference Algorithm and Its
+// Variations", Eugene W. Myers, 1986) -- rather than keeping the whole edit graph around, the
+// "middle snake" of the optimal path is found by running the algorithm forwards and backwards at
+// the same time, and the halves either side of it are solved the same way. The matching blocks
+// make up a longest common subsequence of a and b, so the diff is minimal.
+type myers[T comparable] struct {
+	a       []T
+	b       []T
+	matched []Match
+
+	// furthest reaching x of the forward and reverse paths per diagonal, shared by all the
+	// middleSnake calls since each call only reads what it wrote itself
+	forward []int
+	reverse []int
+}
+
+func newMyers[T comparable](a, b []T) *myers[T] {
+	// one slot per diagonal the paths can reach, plus one either side
+	vLen := 2*((len(a)+len(b)+1)/2) + 3 //nolint:gomnd
+
+	return &myers[T]{
+		a:       a,
+		b:       b,
+		forward: make([]int, vLen),
+		reverse: make([]int, vLen),
+	}
+}
+
+func myersMatchingBlocks[T comparable](a, b []T) []Match {
+	m := newMyers(a, b)
+
+	m.compare(0, len(a), 0, len(b))
+
+	return m.matched
+}
+
+func (m *myers[T]) match(i, j, size int) {
+	if size > 0 {
+		m.matched = append(m.matched, Match{A: i, B: j, Size: size})
+	}
+}
+
+// compare records the matching blocks of a[aLo:aHi] and b[bLo:bHi], in order.
+func (m *myers[T]) compare(aLo, aHi, bLo, bHi int) {
+	prefix := 0
+	for aLo+prefix < aHi && bLo+prefix < bHi && m.a[aLo+prefix] == m.b[bLo+prefix] {
+		prefix++
+	}
+
+	m.match(aLo, bLo, prefix)
+	aLo, bLo = aLo+prefix, bLo+prefix
+
+	suffix := 0
+	for aLo < aHi-suffix && bLo < bHi-suffix && m.a[aHi-suffix-1] == m.b[bHi-suffix-1] {
+		suffix++
+	}
+
+	aHi, bHi = aHi-suffix, bHi-suffix
+
+	if aLo < aHi && bLo < bHi {
+		x, y, u, v := m.middleSnake(aLo, aHi, bLo, bHi)
+
+		m.compare(aLo, x, bLo, y)
+		m.match(x, y, u-x)
+		m.compare(u, aHi, v, bHi)
+	}
+
+	m.match(aHi, bHi, suffix)
+}
+
+// middleSnake returns the start (x, y) and end (u, v) of the middle snake of the optimal path
+// from (aLo, bLo) to (aHi, bHi). The snake may be empty, but it always splits the problem in two
+// smaller ones.
+func (m *myers[T]) middleSnake(aLo, aHi, bLo, bHi int) (int, int, int, int) { //nolint:gocyclo
+	n, mm := aHi-aLo, bHi-bLo
+	delta := n - mm
+	odd := delta%2 != 0
+	maxD := (n + mm + 1) / 2 //nolint:gomnd
+
+	// diagonal k lives at index k+offset
+	offset := maxD + 1
+	vf, vr := m.forward, m.reverse
+	vf[offset+1], vr[offset+1] = 0, 0
+
+	for d := 0; d <= maxD; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+
+			if k == -d || (k != d && vf[offset+k-1] < vf[offset+k+1]) {
+				x = vf[offset+k+1]
+			} else {
+				x = vf[offset+k-1] + 1
+			}
+
+			y := x - k
+			x0, y0 := x, y
+
+			for x < n && y < mm && m.a[aLo+x] == m.b[bLo+y] {
+				x, y = x+1, y+1
+			}
+
+			vf[offset+k] = x
+
+			// the reverse paths have only reached d-1 edits, reverse diagonal delta-k
+			if odd && k >= delta-(d-1) && k <= delta+(d-1) && x+vr[offset+delta-k] >= n {
+				return aLo + x0, bLo + y0, aLo + x, bLo + y
+			}
+		}
+
+		// the reverse paths work back from the ends of the sequences, x and y are the number of
+		// elements from the end
+		for k := -d; k <= d; k += 2 {
+			var x int
+
+			if k == -d || (k != d && vr[offset+k-1] < vr[offset+k+1]) {
+				x = vr[offset+k+1]
+			} else {
+				x = vr[offset+k-1] + 1
+			}
+
+			y := x - k
+			x0, y0 := x, y
+
+			for x < n && y < mm && m.a[aHi-x-1] == m.b[bHi-y-1] {
+				x, y = x+1, y+1
+			}
+
+			vr[offset+k] = x
+
+			if kf := delta - k; !odd && kf >= -d && kf <= d && vf[offset+kf]+x >= n {
+				return aLo + n - x, bLo + mm - y, aLo + n - x0, bLo + mm - y0
+			}
+		}
+	}
+
+	// unreachable, the paths always meet by maxD
+	panic("myers paths did not meet, this shouldn't happen...")
+}
@@ -0,0 +1,368 @@
+package difflibgo
+
+import "regexp"
+
+// Option is a functional option for the diff functions in this package.
+type Option func(*options)
+
+type options struct {
+	fromFile     string
+	toFile       string
+	fromFileDate string
+	toFileDate   string
+	context      int
+	lineTerm     string
+	tabSize      int
+	wrapColumn   int
+	lineJunk     func(string) bool
+	charJunk     func(rune) bool
+	noAutoJunk   bool
+	cutoff       *float64
+	graphemes    bool
+	displayWidth bool
+	words        bool
+	algorithm    Algorithm
+	format       Format
+
+	normalizeLineEndings bool
+	lineEndings          bool
+	ignoreSpaceChange    bool
+	ignoreAllSpace       bool
+	ignoreBlankLines     bool
+	lineKey              func(string) string
+	masks                []mask
+	ignorePatterns       []*regexp.Regexp
+}
+
+func newOptions(opts ...Option) *options {
+	o := &options{
+		context:  defaultContext,
+		lineTerm: defaultLineTerm,
+		tabSize:  defaultTabSize,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// matcherOptions returns the options for the line level SequenceMatcher of the diff functions.
+func (o *options) matcherOptions() []MatcherOption[string] {
+	return []MatcherOption[string]{
+		WithMatcherAutoJunk[string](!o.noAutoJunk),
+		WithMatcherAlgorithm[string](o.algorithm),
+		WithMatcherKey(o.key()),
+	}
+}
+
+// key returns the function that maps lines to the keys they are compared by -- the line ending
+// normalization, then the WithLineKey function, then the WithMaskMatching masks, then the
+// whitespace normalization of the whitespace options -- nil if lines are compared as is. Lines
+// matching the WithIgnoreMatching patterns all share the same key, so they compare equal to one
+// another.
+func (o *options) key() func(string) string {
+	key := o.normalizeKey()
+
+	if len(o.ignorePatterns) == 0 {
+		return key
+	}
+
+	return func(line string) string {
+		if matchesAny(o.ignorePatterns, line) {
+			return ignoredKey
+		}
+
+		if key == nil {
+			return line
+		}
+
+		return key(line)
+	}
+}
+
+// normalizeKey returns the composition of the key functions that normalize lines, nil if there
+// are none.
+func (o *options) normalizeKey() func(string) string {
+	var keys []func(string) string
+
+	if o.normalizeLineEndings {
+		keys = append(keys, normalizeLineEnding)
+	}
+
+	if o.lineKey != nil {
+		keys = append(keys, o.lineKey)
+	}
+
+	if len(o.masks) > 0 {
+		keys = append(keys, maskKey(o.masks))
+	}
+
+	switch {
+	case o.ignoreAllSpace:
+		keys = append(keys, allSpaceKey)
+	case o.ignoreSpaceChange:
+		keys = append(keys, spaceChangeKey)
+	}
+
+	switch len(keys) {
+	case 0:
+		return nil
+	case 1:
+		return keys[0]
+	default:
+		return func(line string) string {
+			for _, key := range keys {
+				line = key(line)
+			}
+
+			return line
+		}
+	}
+}
+
+// ignored returns the function that tells if a line is one whose insertion or deletion is
+// ignored -- see WithIgnoreBlankLines and WithIgnoreMatching -- nil if no lines are.
+func (o *options) ignored() func(string) bool {
+	if !o.ignoreBlankLines && len(o.ignorePatterns) == 0 {
+		return nil
+	}
+
+	key := o.normalizeKey()
+
+	return func(line string) bool {
+		return o.ignoreBlankLines && isBlankLine(line, key) ||
+			matchesAny(o.ignorePatterns, line)
+	}
+}
+
+// groupedOpcodes returns the hunks of the diff of a and b for the unified and context formats.
+func (o *options) groupedOpcodes(a, b []string) [][]OpCode {
+	matcher := NewSequenceMatcher(a, b, o.matcherOptions()...)
+
+	groups := matcher.GetGroupedOpcodes(o.context)
+
+	if ignored := o.ignored(); ignored != nil {
+		groups = dropIgnoredGroups(groups, a, b, ignored)
+	}
+
+	return groups
+}
+
+// WithFromFile sets the file name (and optionally modification time) shown in the header of the
+// "a" side of a diff.
+func WithFromFile(name, date string) Option {
+	return func(o *options) {
+		o.fromFile = name
+		o.fromFileDate = date
+	}
+}
+
+// WithToFile sets the file name (and optionally modification time) shown in the header of the
+// "b" side of a diff.
+func WithToFile(name, date string) Option {
+	return func(o *options) {
+		o.toFile = name
+		o.toFileDate = date
+	}
+}
+
+// WithContext sets the number of context lines shown around each change, defaults to 3.
+func WithContext(n int) Option {
+	return func(o *options) {
+		if n < 0 {
+			n = 0
+		}
+
+		o.context = n
+	}
+}
+
+// WithLineTerm sets the line terminator appended to the control lines (headers and hunk
+// markers) of a diff, defaults to "\n". Set this to "" if your input lines have no trailing
+// newlines, so that all the output lines are uniformly newline free.
+func WithLineTerm(lineTerm string) Option {
+	return func(o *options) {
+		o.lineTerm = lineTerm
+	}
+}
+
+// WithTabSize sets the tab stop spacing HTMLDiff uses when expanding tabs, defaults to 8.
+func WithTabSize(n int) Option {
+	return func(o *options) {
+		o.tabSize = n
+	}
+}
+
+// WithWrapColumn sets the column at which HTMLDiff wraps long lines, defaults to 0 which means
+// lines are not wrapped.
+func WithWrapColumn(n int) Option {
+	return func(o *options) {
+		o.wrapColumn = n
+	}
+}
+
+// WithLineJunk sets the "linejunk" function of a Differ (or HTMLDiff) -- lines for which lineJunk
+// returns true are junk and are never used to sync up the two sequences; see IsLineJunk.
+func WithLineJunk(lineJunk func(string) bool) Option {
+	return func(o *options) {
+		o.lineJunk = lineJunk
+	}
+}
+
+// WithCharJunk sets the "charjunk" function of a Differ (or HTMLDiff) -- characters for which
+// charJunk returns true are junk and are never used to sync up the intraline comparison of
+// similar lines; see IsCharacterJunk.
+func WithCharJunk(charJunk func(rune) bool) Option {
+	return func(o *options) {
+		o.charJunk = charJunk
+	}
+}
+
+// WithAutoJunk enables or disables the "autojunk" heuristic of the underlying SequenceMatchers,
+// see WithMatcherAutoJunk. The heuristic is enabled by default; disabling it trades speed for
+// correctness on inputs with many repeated lines (like the "!" lines of a network device config).
+func WithAutoJunk(autoJunk bool) Option {
+	return func(o *options) {
+		o.noAutoJunk = !autoJunk
+	}
+}
+
+// WithCutoff sets the similarity ratio (see SequenceMatcher.Ratio) two lines of a replaced block
+// must reach for a Differ to consider them "similar" and mark their differences up with "?"
+// guide lines, defaults to 0.75 as in python. Lower values get you intraline hints for lines that
+// are less alike, and a cutoff of 1 switches the intraline hints off altogether.
+func WithCutoff(cutoff float64) Option {
+	return func(o *options) {
+		o.cutoff = &cutoff
+	}
+}
+
+// WithGraphemeClusters makes a Differ (or HTMLDiff) compare similar lines grapheme cluster by
+// grapheme cluster rather than rune by rune -- so that, for example, an "e" followed by a
+// combining accent is a single character that is either changed or not. Disabled by default, as
+// python compares code points.
+func WithGraphemeClusters(enabled bool) Option {
+	return func(o *options) {
+		o.graphemes = enabled
+	}
+}
+
+// WithDisplayWidth makes a Differ align the markers of its "?" guide lines to the display width
+// of the characters they mark -- two columns for wide (CJK, emoji) characters, none for zero
+// width ones -- so that the markers line up in a terminal. Disabled by default, as python emits a
+// marker per code point. HTMLDiff ignores this option.
+func WithDisplayWidth(enabled bool) Option {
+	return func(o *options) {
+		o.displayWidth = enabled
+	}
+}
+
+// WithWordDiff makes a Differ (or HTMLDiff) compare similar lines word by word rather than
+// character by character -- the lines are split into runs of letters, digits and underscores,
+// runs of whitespace and single punctuation characters, and the intraline changes (the Spans and
+// "?" guides) always cover whole words. A lot easier on the eyes for long lines of prose, such as
+// descriptions and banners, which character by character come out as a mess of single "^"s.
+// Lines are also paired up by how alike their words are. See also WordDiff.
+func WithWordDiff(enabled bool) Option {
+	return func(o *options) {
+		o.words = enabled
+	}
+}
+
+// WithAlgorithm sets the algorithm the SequenceMatchers of a diff use, see Algorithm; defaults
+// to AlgorithmRatcliffObershelp, python's algorithm. For a Differ (or HTMLDiff) this applies to
+// both the line level comparison and the intraline comparison of similar lines.
+func WithAlgorithm(algorithm Algorithm) Option {
+	return func(o *options) {
+		o.algorithm = algorithm
+	}
+}
+
+// WithFormat sets the output format of DiffReaders, defaults to FormatUnified.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format = format
+	}
+}
+
+// WithNormalizeLineEndings makes the diff functions compare lines ending in "\r\n" equal to
+// the same lines ending in "\n", so that a file converted from or to windows line endings does
+// not show up as changed entirely. As with the whitespace options the "a" side of lines that
+// compare equal is shown. Only matters for lines that keep their terminators (see SplitLines),
+// the diff functions that split strings themselves drop them anyway. HTMLDiff ignores this
+// option.
+func WithNormalizeLineEndings(enabled bool) Option {
+	return func(o *options) {
+		o.normalizeLineEndings = enabled
+	}
+}
+
+// WithIgnoreSpaceChange makes the diff functions ignore changes in the amount of whitespace, like
+// diff -b: runs of whitespace compare equal to a single space and trailing whitespace is ignored.
+// Lines that only differ in whitespace are shown as unchanged, with the text of the "a" side.
+// HTMLDiff ignores this option.
+func WithIgnoreSpaceChange(enabled bool) Option {
+	return func(o *options) {
+		o.ignoreSpaceChange = enabled
+	}
+}
+
+// WithIgnoreAllSpace makes the diff functions ignore all whitespace when comparing lines, like
+// diff -w. As with WithIgnoreSpaceChange the "a" side of lines that compare equal is shown.
+// HTMLDiff ignores this option.
+func WithIgnoreAllSpace(enabled bool) Option {
+	return func(o *options) {
+		o.ignoreAllSpace = enabled
+	}
+}
+
+// WithIgnoreBlankLines makes the diff functions ignore changes that only insert or delete blank
+// lines, like diff -B. The unified and context diffs drop hunks that only change blank lines, a
+// Differ emits the lines of such changes as LineIgnored rather than LineDelete or LineInsert
+// (blank lines deleted or inserted along with other lines are still shown as changes). Combined
+// with WithIgnoreSpaceChange or WithIgnoreAllSpace whitespace only lines count as blank. HTMLDiff
+// ignores this option.
+func WithIgnoreBlankLines(enabled bool) Option {
+	return func(o *options) {
+		o.ignoreBlankLines = enabled
+	}
+}
+
+// WithLineKey sets a key function the lines are compared by -- lines are matched up on their keys
+// but output as they are, so a key that lowercases lines, or masks out timestamps and counters,
+// stops lines that only differ in case, times or counts showing up as changes. A Differ also
+// uses the keys to find the similar lines it compares character by character (the characters
+// shown as changed are those of the original lines). See also WithMatcherKey. HTMLDiff ignores
+// this option.
+func WithLineKey(key func(string) string) Option {
+	return func(o *options) {
+		o.lineKey = key
+	}
+}
+
+// WithIgnoreMatching makes the diff functions ignore the lines matching any of the patterns, like
+// diff -I: such lines compare equal to one another, and changes that only insert or delete such
+// lines are ignored as for WithIgnoreBlankLines -- the unified and context diffs drop the hunks
+// that only change such lines, a Differ emits them as LineIgnored. The patterns are matched against
+// the original lines. May be given more than once, the patterns add up. HTMLDiff ignores this
+// option.
+func WithIgnoreMatching(patterns ...*regexp.Regexp) Option {
+	return func(o *options) {
+		o.ignorePatterns = append(o.ignorePatterns, patterns...)
+	}
+}
+
+// WithMaskMatching replaces the matches of the patterns with placeholder before lines are
+// compared, so that volatile fields -- timestamps, serial numbers, hashes -- do not show up as
+// changes; the lines are still output as they are. The masks are applied after the WithLineKey
+// function (if any). May be given more than once, say with different placeholders, the masks are
+// applied in order. HTMLDiff ignores this option.
+func WithMaskMatching(placeholder string, patterns ...*regexp.Regexp) Option {
+	return func(o *options) {
+		for _, pattern := range patterns {
+			o.masks = append(o.masks, mask{pattern: pattern, placeholder: placeholder})
+		}
+	}
+}
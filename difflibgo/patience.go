@@ -0,0 +1,158 @@
+package difflibgo
+
+// trimCommon returns r without the elements common to the start and end of a[r.aLo:r.aHi] and
+// b[r.bLo:r.bHi], appending the common blocks to matched.
+func trimCommon[T comparable](a, b []T, r matchRange, matched []Match) (matchRange, []Match) {
+	prefix := 0
+	for r.aLo+prefix < r.aHi && r.bLo+prefix < r.bHi && a[r.aLo+prefix] == b[r.bLo+prefix] {
+		prefix++
+	}
+
+	if prefix > 0 {
+		matched = append(matched, Match{A: r.aLo, B: r.bLo, Size: prefix})
+		r.aLo, r.bLo = r.aLo+prefix, r.bLo+prefix
+	}
+
+	suffix := 0
+	for r.aLo < r.aHi-suffix && r.bLo < r.bHi-suffix &&
+		a[r.aHi-suffix-1] == b[r.bHi-suffix-1] {
+		suffix++
+	}
+
+	if suffix > 0 {
+		r.aHi, r.bHi = r.aHi-suffix, r.bHi-suffix
+		matched = append(matched, Match{A: r.aHi, B: r.bHi, Size: suffix})
+	}
+
+	return r, matched
+}
+
+type patienceCount struct {
+	countA int
+	countB int
+	indexA int
+	indexB int
+}
+
+// patienceAnchors returns the elements unique to both a[r.aLo:r.aHi] and b[r.bLo:r.bHi] that
+// make up the longest run in the same order in both -- found by "patience sorting" them.
+func patienceAnchors[T comparable](a, b []T, r matchRange) []Match {
+	counts := map[T]*patienceCount{}
+
+	for i := r.aLo; i < r.aHi; i++ {
+		c, ok := counts[a[i]]
+		if !ok {
+			c = &patienceCount{}
+			counts[a[i]] = c
+		}
+
+		c.countA++
+		c.indexA = i
+	}
+
+	for j := r.bLo; j < r.bHi; j++ {
+		if c, ok := counts[b[j]]; ok {
+			c.countB++
+			c.indexB = j
+		}
+	}
+
+	var uniques []Match
+
+	for i := r.aLo; i < r.aHi; i++ {
+		if c := counts[a[i]]; c.countA == 1 && c.countB == 1 {
+			uniques = append(uniques, Match{A: c.indexA, B: c.indexB, Size: 1})
+		}
+	}
+
+	if len(uniques) == 0 {
+		return nil
+	}
+
+	// tops holds the index (into uniques) of the top card of each pile, each card remembers the
+	// top card of the pile to its left when it was placed
+	var tops []int
+
+	previous := make([]int, len(uniques))
+
+	for idx, unique := range uniques {
+		// binary search for the leftmost pile whose top card is after this one in b
+		lo, hi := 0, len(tops)
+		for lo < hi {
+			mid := (lo + hi) / 2 //nolint:gomnd
+			if uniques[tops[mid]].B < unique.B {
+				lo = mid + 1
+			} else {
+				hi = mid
+			}
+		}
+
+		previous[idx] = -1
+		if lo > 0 {
+			previous[idx] = tops[lo-1]
+		}
+
+		if lo == len(tops) {
+			tops = append(tops, idx)
+		} else {
+			tops[lo] = idx
+		}
+	}
+
+	anchors := make([]Match, len(tops))
+
+	for idx, k := tops[len(tops)-1], len(tops)-1; idx >= 0; idx, k = previous[idx], k-1 {
+		anchors[k] = uniques[idx]
+	}
+
+	return anchors
+}
+
+// appendAnchored appends the regions of r between the (single element) anchors to pending.
+func appendAnchored(pending []matchRange, r matchRange, anchors []Match) []matchRange {
+	aLo, bLo := r.aLo, r.bLo
+
+	for _, anchor := range anchors {
+		pending = append(pending, matchRange{aLo, anchor.A, bLo, anchor.B})
+		aLo, bLo = anchor.A+1, anchor.B+1
+	}
+
+	return append(pending, matchRange{aLo, r.aHi, bLo, r.bHi})
+}
+
+// patienceMatchingBlocks returns the matching blocks of a and b found with patience diff, sorted
+// by A and B. Ranges without unique common elements are diffed with Myers' algorithm.
+func patienceMatchingBlocks[T comparable](a, b []T) []Match {
+	fallback := newMyers(a, b)
+
+	var matched []Match
+
+	pending := []matchRange{{0, len(a), 0, len(b)}}
+
+	for len(pending) > 0 {
+		var r matchRange
+
+		r, pending = pending[len(pending)-1], pending[:len(pending)-1]
+		r, matched = trimCommon(a, b, r, matched)
+
+		if r.aLo == r.aHi || r.bLo == r.bHi {
+			continue
+		}
+
+		anchors := patienceAnchors(a, b, r)
+		if len(anchors) == 0 {
+			fallback.compare(r.aLo, r.aHi, r.bLo, r.bHi)
+
+			continue
+		}
+
+		pending = appendAnchored(pending, r, anchors)
+		matched = append(matched, anchors...)
+	}
+
+	matched = append(matched, fallback.matched...)
+
+	sortMatches(matched)
+
+	return matched
+}
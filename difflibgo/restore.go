@@ -0,0 +1,54 @@
+package difflibgo
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrInvalidWhich is returned by Restore when which is not 1 or 2.
+	ErrInvalidWhich = errors.New("which must be 1 or 2")
+	// ErrMalformedDelta is returned by Restore when a line of the delta is not valid Differ
+	// output.
+	ErrMalformedDelta = errors.New("malformed delta")
+)
+
+// Restore is a port of python's difflib.restore -- it takes the output of Differ.Compare and
+// returns one of the two sequences that produced it; sequence a if which is 1, or sequence b if
+// which is 2. Unlike python, Restore checks every line of the delta and returns
+// ErrMalformedDelta if a line does not start with one of the Differ codes ("  ", "- ", "+ " or
+// "? ").
+func Restore(delta []string, which int) ([]string, error) {
+	var tag string
+
+	switch which {
+	case 1:
+		tag = diffSubtraction
+	case 2: //nolint:gomnd
+		tag = diffAddition
+	default:
+		return nil, fmt.Errorf("%w, got %d", ErrInvalidWhich, which)
+	}
+
+	var restored []string
+
+	for idx, line := range delta {
+		if len(line) < 2 { //nolint:gomnd
+			return nil, fmt.Errorf(
+				"%w: line %d %q is missing its code", ErrMalformedDelta, idx+1, line,
+			)
+		}
+
+		switch line[:2] {
+		case diffEqual, tag:
+			restored = append(restored, line[2:])
+		case diffSubtraction, diffAddition, diffUnknown:
+		default:
+			return nil, fmt.Errorf(
+				"%w: line %d %q has unknown code %q", ErrMalformedDelta, idx+1, line, line[:2],
+			)
+		}
+	}
+
+	return restored, nil
+}
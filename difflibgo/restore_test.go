@@ -0,0 +1,72 @@
+package difflibgo_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestRestore(t *testing.T) {
+	a := []string{"one\n", "two\n", "three\n", "interface Gi0/1\n"}
+	b := []string{"ore\n", "tree\n", "emu\n", "interface Gi0/2\n"}
+
+	delta := difflibgo.Compare(a, b)
+
+	cases := []struct {
+		name        string
+		delta       []string
+		which       int
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name:     "restore-a",
+			delta:    delta,
+			which:    1,
+			expected: a,
+		},
+		{
+			name:     "restore-b",
+			delta:    delta,
+			which:    2,
+			expected: b,
+		},
+		{
+			name:        "invalid-which",
+			delta:       delta,
+			which:       3,
+			expectedErr: difflibgo.ErrInvalidWhich,
+		},
+		{
+			name:        "unknown-code",
+			delta:       []string{"  one\n", "* two\n"},
+			which:       1,
+			expectedErr: difflibgo.ErrMalformedDelta,
+		},
+		{
+			name:        "missing-code",
+			delta:       []string{"  one\n", "\n"},
+			which:       2,
+			expectedErr: difflibgo.ErrMalformedDelta,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual, err := difflibgo.Restore(testCase.delta, testCase.which)
+
+				if !errors.Is(err, testCase.expectedErr) {
+					t.Fatalf("expected error %v, got %v", testCase.expectedErr, err)
+				}
+
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
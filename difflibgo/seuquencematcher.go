@@ -4,207 +4,256 @@ import (
 	"sort"
 )
 
-// sequenceMatcher is a port of the python standard library difflib.SequenceMatcher into go. The
+// Match represents a matching block of two sequences -- a[A:A+Size] == b[B:B+Size].
+type Match struct {
+	A    int
+	B    int
+	Size int
+}
+
+// OpCode describes how to turn a[SeqALo:SeqAHi] into b[SeqBLo:SeqBHi]; Tag is one of OpReplace,
+// OpDelete, OpInsert or OpEqual.
+type OpCode struct {
+	Tag    byte
+	SeqALo int
+	SeqAHi int
+	SeqBLo int
+	SeqBHi int
+}
+
+// SequenceMatcher is a port of the python standard library difflib.SequenceMatcher into go. The
 // original class is here: https://github.com/python/cpython/blob/main/Lib/difflib.py#L44. This
-// version only works to compare slices of strings, and removes the `junk` components of the python
-// implementation.
-type sequenceMatcher struct {
-	sequenceA      []string
-	sequenceB      []string
-	matchingBlocks []match
-	opCodes        []opCode
+// version compares slices of any comparable element type -- lines are compared as a []string,
+// characters of a line as a []byte and so on.
+type SequenceMatcher[T comparable] struct {
+	isJunk     func(T) bool
+	noAutoJunk bool
+	algorithm  Algorithm
+	key        func(T) T
+
+	sequenceA      []T
+	sequenceB      []T
+	matchingBlocks []Match
+	opCodes        []OpCode
 
 	// indices of things in b that are not junk; "b2j" in difflib
-	bNonJunkIndicies map[string][]int
+	bNonJunkIndicies map[T][]int
 
 	// things deemed "auto junk" by the heuristic; "bpopular" in difflib
-	bAutoJunk  map[string]struct{}
-	fullBCount map[string]int
-}
+	bAutoJunk map[T]struct{}
 
-func (s *sequenceMatcher) setSequences(a, b []string) {
-	s.setSequenceA(a)
-	s.setSequenceB(b)
-}
+	// things deemed junk by the isJunk function; "bjunk" in difflib
+	bJunk map[T]struct{}
 
-func (s *sequenceMatcher) setSequenceA(a []string) {
-	if &a == &s.sequenceA {
-		return
-	}
+	fullBCount map[T]int
 
-	s.sequenceA = a
-	s.matchingBlocks = nil
-	s.opCodes = nil
+	// scratch maps for FindLongestMatch
+	j2len [2]map[int]int
 }
 
-func (s *sequenceMatcher) setSequenceB(b []string) {
-	if &b == &s.sequenceB {
-		return
-	}
-
-	s.sequenceB = b
-	s.matchingBlocks = nil
-	s.opCodes = nil
-	s.fullBCount = nil
+// Algorithm selects how a SequenceMatcher finds the matching blocks of its sequences.
+type Algorithm int
+
+const (
+	// AlgorithmRatcliffObershelp is python's algorithm and the default -- find the longest
+	// matching block, then recursively do the same to the left and right of it. It does not
+	// necessarily produce minimal diffs, but they tend to "look right" to people. This is the
+	// only algorithm that honors the isjunk function and the autojunk heuristic.
+	AlgorithmRatcliffObershelp Algorithm = iota
+	// AlgorithmMyers is Myers' O(ND) algorithm (in linear space), as used by diff and git by
+	// default. It produces minimal diffs (a longest common subsequence), and is much faster than
+	// AlgorithmRatcliffObershelp on large, similar sequences.
+	AlgorithmMyers
+	// AlgorithmPatience is Bram Cohen's patience diff -- the elements that occur exactly once in
+	// both sequences are matched up first (as long as they are in the same order), and the gaps
+	// between them are diffed the same way, falling back to AlgorithmMyers where there are no
+	// unique elements. Unique elements are good anchors; reordered blocks and repeated
+	// boilerplate ("!", "}", "exit") don't confuse it.
+	AlgorithmPatience
+	// AlgorithmHistogram is git's histogram diff, an extension of patience diff -- the longest
+	// matching block containing the least frequently occurring elements is matched first, and the
+	// blocks either side of it are diffed the same way. Like AlgorithmPatience it prefers rare
+	// elements as anchors, but also handles sequences with few unique elements well. Very large
+	// regions are split on their unique elements first, as AlgorithmPatience does, so that large,
+	// similar sequences are matched up in (roughly) linear time.
+	AlgorithmHistogram
+)
 
-	s.purgeAutoJunk()
+// matchRange is a[aLo:aHi] and b[bLo:bHi], a region of the sequences still to be matched up.
+type matchRange struct {
+	aLo int
+	aHi int
+	bLo int
+	bHi int
 }
 
-func (s *sequenceMatcher) purgeAutoJunkElement() {
-	s.bNonJunkIndicies = map[string][]int{}
-
-	seqB := s.sequenceB[0]
+// MatcherOption is a functional option for NewSequenceMatcher.
+type MatcherOption[T comparable] func(*SequenceMatcher[T])
 
-	for i, seq := range seqB {
-		indices := s.bNonJunkIndicies[string(seq)]
-		indices = append(indices, i)
-		s.bNonJunkIndicies[string(seq)] = indices
+// WithMatcherIsJunk sets the "isjunk" function of the matcher -- elements of b for which isJunk
+// returns true are "junk" and are never used to start (or "anchor") a match, though matches may
+// be extended over them. For example, pass a function that returns true for blank lines to avoid
+// syncing up on them.
+func WithMatcherIsJunk[T comparable](isJunk func(T) bool) MatcherOption[T] {
+	return func(s *SequenceMatcher[T]) {
+		s.isJunk = isJunk
 	}
+}
 
-	autoJunk := map[string]struct{}{}
-
-	n := len(seqB)
-
-	if n < autoJunkLenHeuristic {
-		return
-	}
-
-	ntest := n/oneHundred + 1
-
-	for seq, indices := range s.bNonJunkIndicies {
-		if len(indices) > ntest {
-			autoJunk[seq] = struct{}{}
-		}
+// WithMatcherAutoJunk enables or disables the "autojunk" heuristic, which is enabled by default.
+// With the heuristic enabled, if b has 200 or more elements, any element that accounts for more
+// than 1% of b is deemed "popular" and treated as junk. This speeds things up considerably for
+// large inputs at the cost of (sometimes much) less minimal diffs.
+func WithMatcherAutoJunk[T comparable](autoJunk bool) MatcherOption[T] {
+	return func(s *SequenceMatcher[T]) {
+		s.noAutoJunk = !autoJunk
 	}
+}
 
-	for seq := range autoJunk {
-		delete(s.bNonJunkIndicies, seq)
+// WithMatcherAlgorithm sets the algorithm used to find the matching blocks (and so the opcodes) of
+// the sequences, defaults to AlgorithmRatcliffObershelp.
+func WithMatcherAlgorithm[T comparable](algorithm Algorithm) MatcherOption[T] {
+	return func(s *SequenceMatcher[T]) {
+		s.algorithm = algorithm
 	}
-
-	s.bAutoJunk = autoJunk
 }
 
-func (s *sequenceMatcher) purgeAutoJunkSlice() {
-	s.bNonJunkIndicies = map[string][]int{}
-
-	for i, seq := range s.sequenceB {
-		indices := s.bNonJunkIndicies[seq]
-		indices = append(indices, i)
-		s.bNonJunkIndicies[seq] = indices
+// WithMatcherKey sets a key function for the matcher -- elements are matched on their keys rather
+// than on their values, so for example a key that lowercases lines, or blanks out timestamps and
+// counters, matches up lines that only differ in case, times or counts. The matching blocks and
+// opcodes are indices into the sequences, so the callers output the original elements. Note that
+// the isjunk function (and the autojunk heuristic) sees the keys.
+func WithMatcherKey[T comparable](key func(T) T) MatcherOption[T] {
+	return func(s *SequenceMatcher[T]) {
+		s.key = key
 	}
+}
 
-	autoJunk := map[string]struct{}{}
-
-	n := len(s.sequenceB)
+// NewSequenceMatcher returns a SequenceMatcher comparing sequences a and b.
+func NewSequenceMatcher[T comparable](a, b []T, opts ...MatcherOption[T]) *SequenceMatcher[T] {
+	s := &SequenceMatcher[T]{}
 
-	if n < autoJunkLenHeuristic {
-		return
+	for _, opt := range opts {
+		opt(s)
 	}
 
-	ntest := n/oneHundred + 1
+	s.SetSeqs(a, b)
 
-	for seq, indices := range s.bNonJunkIndicies {
-		if len(indices) > ntest {
-			autoJunk[seq] = struct{}{}
-		}
-	}
-
-	for seq := range autoJunk {
-		delete(s.bNonJunkIndicies, seq)
-	}
+	return s
+}
 
-	s.bAutoJunk = autoJunk
+// SetSeqs sets the two sequences to be compared.
+func (s *SequenceMatcher[T]) SetSeqs(a, b []T) {
+	s.SetSeq1(a)
+	s.SetSeq2(b)
 }
 
-func (s *sequenceMatcher) purgeAutoJunk() {
-	if len(s.sequenceB) == 1 {
-		s.purgeAutoJunkElement()
-	} else {
-		s.purgeAutoJunkSlice()
-	}
+// SetSeq1 sets the first sequence to be compared, the second sequence is not changed. The
+// matcher caches detailed information about the second sequence, so if you want to compare one
+// sequence against many, set the one with SetSeq2 and repeatedly call SetSeq1 for the others.
+func (s *SequenceMatcher[T]) SetSeq1(a []T) {
+	s.sequenceA = s.keyed(a)
+	s.matchingBlocks = nil
+	s.opCodes = nil
 }
 
-func (s *sequenceMatcher) isBSeqJunk(seq string) bool {
-	_, ok := s.bAutoJunk[seq]
+// SetSeq2 sets the second sequence to be compared, the first sequence is not changed.
+func (s *SequenceMatcher[T]) SetSeq2(b []T) {
+	s.sequenceB = s.keyed(b)
+	s.matchingBlocks = nil
+	s.opCodes = nil
+	s.fullBCount = nil
 
-	return ok
+	s.purgeJunk()
 }
 
-func (s *sequenceMatcher) findLongestMatchSingleElement( //nolint:gocyclo
-	seqALo,
-	seqAHi,
-	seqBLo,
-	seqBHi int,
-) match {
-	besti, bestj, bestsize := seqALo, seqBLo, 0
-	j2len := map[int]int{}
+// keyed returns the keys of seq, or seq itself if the matcher has no key function.
+func (s *SequenceMatcher[T]) keyed(seq []T) []T {
+	if s.key == nil {
+		return seq
+	}
 
-	seqA, seqB := s.sequenceA[0], s.sequenceB[0]
+	keys := make([]T, len(seq))
 
-	if seqA == "" || seqB == "" {
-		return match{
-			A:    0,
-			B:    0,
-			Size: 0,
-		}
+	for idx, elem := range seq {
+		keys[idx] = s.key(elem)
 	}
 
-	for i := seqALo; i != seqAHi; i++ {
-		newj2len := map[int]int{}
+	return keys
+}
 
-		for _, j := range s.bNonJunkIndicies[string(seqA[i])] {
-			if j < seqBLo {
-				continue
-			}
+func (s *SequenceMatcher[T]) purgeJunk() {
+	s.bNonJunkIndicies = map[T][]int{}
 
-			if j >= seqBHi {
-				break
-			}
+	for i, elem := range s.sequenceB {
+		s.bNonJunkIndicies[elem] = append(s.bNonJunkIndicies[elem], i)
+	}
 
-			k := j2len[j-1] + 1
-			newj2len[j] = k
+	s.bJunk = map[T]struct{}{}
+	s.bAutoJunk = map[T]struct{}{}
 
-			if k > bestsize {
-				besti, bestj, bestsize = i-k+1, j-k+1, k
+	if s.isJunk != nil {
+		for elem := range s.bNonJunkIndicies {
+			if s.isJunk(elem) {
+				s.bJunk[elem] = struct{}{}
 			}
 		}
 
-		j2len = newj2len
+		for elem := range s.bJunk {
+			delete(s.bNonJunkIndicies, elem)
+		}
 	}
 
-	for besti > seqALo && bestj > seqBLo && !s.isBSeqJunk(string(seqB[bestj-1])) &&
-		string(seqA[besti-1]) == string(seqB[bestj-1]) {
-		besti, bestj, bestsize = besti-1, bestj-1, bestsize+1
-	}
+	n := len(s.sequenceB)
 
-	for besti+bestsize < seqAHi && bestj+bestsize < seqBHi &&
-		!s.isBSeqJunk(string(seqB[bestj+bestsize])) &&
-		string(seqA[besti+bestsize]) == string(seqB[bestj+bestsize]) {
-		bestsize++
+	if s.noAutoJunk || n < autoJunkLenHeuristic {
+		return
 	}
 
-	for besti > seqALo && bestj > seqBLo && s.isBSeqJunk(string(seqB[bestj-1])) &&
-		string(seqA[besti-1]) == string(seqB[bestj-1]) {
-		besti, bestj, bestsize = besti-1, bestj-1, bestsize+1
+	ntest := n/oneHundred + 1
+
+	for elem, indices := range s.bNonJunkIndicies {
+		if len(indices) > ntest {
+			s.bAutoJunk[elem] = struct{}{}
+		}
 	}
 
-	for besti+bestsize < seqAHi && bestj+bestsize < seqBHi &&
-		s.isBSeqJunk(string(seqB[bestj+bestsize])) &&
-		string(seqA[besti+bestsize]) == string(seqB[bestj+bestsize]) {
-		bestsize++
+	for elem := range s.bAutoJunk {
+		delete(s.bNonJunkIndicies, elem)
 	}
+}
+
+func (s *SequenceMatcher[T]) isBSeqJunk(elem T) bool {
+	_, ok := s.bJunk[elem]
 
-	return match{A: besti, B: bestj, Size: bestsize}
+	return ok
 }
 
-func (s *sequenceMatcher) findLongestMatchSlice(seqALo, seqAHi, seqBLo, seqBHi int) match {
+// FindLongestMatch finds the longest matching block in a[seqALo:seqAHi] and b[seqBLo:seqBHi].
+// Of all maximal matching blocks it returns the one that starts earliest in a, and of those, the
+// one that starts earliest in b. If no blocks match, the returned Match has a Size of zero.
+func (s *SequenceMatcher[T]) FindLongestMatch(seqALo, seqAHi, seqBLo, seqBHi int) Match {
+	seqA, seqB := s.sequenceA, s.sequenceB
+
 	besti, bestj, bestsize := seqALo, seqBLo, 0
-	j2len := map[int]int{}
 
-	for i := seqALo; i != seqAHi; i++ {
-		newj2len := map[int]int{}
+	// j2len[j] is the length of the longest match ending with a[i-1] and b[j], newj2len the same
+	// for a[i]; the two maps are swapped and reused rather than allocating a map per element of a
+	j2len, newj2len := s.j2len[0], s.j2len[1]
+	if j2len == nil {
+		j2len, newj2len = map[int]int{}, map[int]int{}
+	}
+
+	for k := range j2len {
+		delete(j2len, k)
+	}
 
-		for _, j := range s.bNonJunkIndicies[s.sequenceA[i]] {
+	for i := seqALo; i < seqAHi; i++ {
+		for k := range newj2len {
+			delete(newj2len, k)
+		}
+
+		for _, j := range s.bNonJunkIndicies[seqA[i]] {
 			if j < seqBLo {
 				continue
 			}
@@ -221,75 +270,73 @@ func (s *sequenceMatcher) findLongestMatchSlice(seqALo, seqAHi, seqBLo, seqBHi i
 			}
 		}
 
-		j2len = newj2len
+		j2len, newj2len = newj2len, j2len
 	}
 
-	for besti > seqALo && bestj > seqBLo && !s.isBSeqJunk(s.sequenceB[bestj-1]) &&
-		s.sequenceA[besti-1] == s.sequenceB[bestj-1] {
+	s.j2len = [2]map[int]int{j2len, newj2len}
+
+	for besti > seqALo && bestj > seqBLo && !s.isBSeqJunk(seqB[bestj-1]) &&
+		seqA[besti-1] == seqB[bestj-1] {
 		besti, bestj, bestsize = besti-1, bestj-1, bestsize+1
 	}
 
 	for besti+bestsize < seqAHi && bestj+bestsize < seqBHi &&
-		!s.isBSeqJunk(s.sequenceB[bestj+bestsize]) &&
-		s.sequenceA[besti+bestsize] == s.sequenceB[bestj+bestsize] {
+		!s.isBSeqJunk(seqB[bestj+bestsize]) &&
+		seqA[besti+bestsize] == seqB[bestj+bestsize] {
 		bestsize++
 	}
 
-	for besti > seqALo && bestj > seqBLo && s.isBSeqJunk(s.sequenceB[bestj-1]) &&
-		s.sequenceA[besti-1] == s.sequenceB[bestj-1] {
+	for besti > seqALo && bestj > seqBLo && s.isBSeqJunk(seqB[bestj-1]) &&
+		seqA[besti-1] == seqB[bestj-1] {
 		besti, bestj, bestsize = besti-1, bestj-1, bestsize+1
 	}
 
 	for besti+bestsize < seqAHi && bestj+bestsize < seqBHi &&
-		s.isBSeqJunk(s.sequenceB[bestj+bestsize]) &&
-		s.sequenceA[besti+bestsize] == s.sequenceB[bestj+bestsize] {
+		s.isBSeqJunk(seqB[bestj+bestsize]) &&
+		seqA[besti+bestsize] == seqB[bestj+bestsize] {
 		bestsize++
 	}
 
-	return match{A: besti, B: bestj, Size: bestsize}
+	return Match{A: besti, B: bestj, Size: bestsize}
 }
 
-func (s *sequenceMatcher) findLongestMatch(seqALo, seqAHi, seqBLo, seqBHi int) match {
-	if len(s.sequenceA) == 1 && len(s.sequenceB) == 1 {
-		return s.findLongestMatchSingleElement(seqALo, seqAHi, seqBLo, seqBHi)
-	}
+// ratcliffObershelpMatchingBlocks returns the matching blocks found by repeatedly finding the
+// longest match, sorted by A and B. As in python, the regions left to match are kept on an
+// explicit stack rather than recursing, so huge inputs can't blow up the call stack.
+func (s *SequenceMatcher[T]) ratcliffObershelpMatchingBlocks() []Match {
+	var matched []Match
 
-	return s.findLongestMatchSlice(seqALo, seqAHi, seqBLo, seqBHi)
-}
-
-func (s *sequenceMatcher) getMatchingBlocks() []match {
-	if s.matchingBlocks != nil {
-		return s.matchingBlocks
-	}
+	pending := []matchRange{{0, len(s.sequenceA), 0, len(s.sequenceB)}}
 
-	var matchBlocks func(alo, ahi, blo, bhi int, matched []match) []match
+	for len(pending) > 0 {
+		r := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
 
-	matchBlocks = func(seqALo, seqAHi, seqBLo, seqBHi int, matched []match) []match {
-		longestMatch := s.findLongestMatch(seqALo, seqAHi, seqBLo, seqBHi)
+		longestMatch := s.FindLongestMatch(r.aLo, r.aHi, r.bLo, r.bHi)
 		i, j, k := longestMatch.A, longestMatch.B, longestMatch.Size
 
-		if longestMatch.Size > 0 {
-			matched = append(matched, longestMatch)
+		if k == 0 {
+			continue
+		}
 
-			if seqALo < i && seqBLo < j {
-				matched = matchBlocks(seqALo, i, seqBLo, j, matched)
-			}
+		matched = append(matched, longestMatch)
 
-			if i+k < seqAHi && j+k < seqBHi {
-				matched = matchBlocks(i+k, seqAHi, j+k, seqBHi, matched)
-			}
+		if r.aLo < i && r.bLo < j {
+			pending = append(pending, matchRange{r.aLo, i, r.bLo, j})
 		}
 
-		return matched
+		if i+k < r.aHi && j+k < r.bHi {
+			pending = append(pending, matchRange{i + k, r.aHi, j + k, r.bHi})
+		}
 	}
 
-	la, lb := len(s.sequenceA), len(s.sequenceB)
-	if len(s.sequenceA) == 1 && len(s.sequenceB) == 1 {
-		la, lb = len(s.sequenceA), len(s.sequenceB)
-	}
+	sortMatches(matched)
 
-	matched := matchBlocks(0, la, 0, lb, nil)
+	return matched
+}
 
+// sortMatches sorts matched by A, then B, then Size.
+func sortMatches(matched []Match) {
 	sort.Slice(matched, func(i, j int) bool {
 		// this *should*(?) match how the python implementation named tuple sorting works...
 		if matched[i].A != matched[j].A {
@@ -302,8 +349,32 @@ func (s *sequenceMatcher) getMatchingBlocks() []match {
 
 		return matched[i].Size < matched[j].Size
 	})
+}
+
+// GetMatchingBlocks returns the list of triples describing non-overlapping matching subsequences.
+// The blocks are ordered by A and B, and the last block is always a "dummy" of
+// Match{len(a), len(b), 0}.
+func (s *SequenceMatcher[T]) GetMatchingBlocks() []Match {
+	if s.matchingBlocks != nil {
+		return s.matchingBlocks
+	}
 
-	var nonAdjacent []match
+	la, lb := len(s.sequenceA), len(s.sequenceB)
+
+	var matched []Match
+
+	switch s.algorithm {
+	case AlgorithmMyers:
+		matched = myersMatchingBlocks(s.sequenceA, s.sequenceB)
+	case AlgorithmPatience:
+		matched = patienceMatchingBlocks(s.sequenceA, s.sequenceB)
+	case AlgorithmHistogram:
+		matched = histogramMatchingBlocks(s.sequenceA, s.sequenceB)
+	default:
+		matched = s.ratcliffObershelpMatchingBlocks()
+	}
+
+	var nonAdjacent []Match
 
 	i1, j1, k1 := 0, 0, 0
 
@@ -313,7 +384,7 @@ func (s *sequenceMatcher) getMatchingBlocks() []match {
 			k1 += k2
 		} else {
 			if k1 > 0 {
-				nonAdjacent = append(nonAdjacent, match{i1, j1, k1})
+				nonAdjacent = append(nonAdjacent, Match{i1, j1, k1})
 			}
 
 			i1, j1, k1 = i2, j2, k2
@@ -321,25 +392,27 @@ func (s *sequenceMatcher) getMatchingBlocks() []match {
 	}
 
 	if k1 > 0 {
-		nonAdjacent = append(nonAdjacent, match{i1, j1, k1})
+		nonAdjacent = append(nonAdjacent, Match{i1, j1, k1})
 	}
 
-	nonAdjacent = append(nonAdjacent, match{la, lb, 0})
+	nonAdjacent = append(nonAdjacent, Match{la, lb, 0})
 
 	s.matchingBlocks = nonAdjacent
 
 	return s.matchingBlocks
 }
 
-func (s *sequenceMatcher) getOpcodes() []opCode {
+// GetOpcodes returns the list of OpCodes describing how to turn a into b. The first OpCode always
+// starts at zero in both sequences, and each subsequent OpCode starts where the prior one ended.
+func (s *SequenceMatcher[T]) GetOpcodes() []OpCode {
 	if s.opCodes != nil {
 		return s.opCodes
 	}
 
 	i, j := 0, 0
-	matching := s.getMatchingBlocks()
+	matching := s.GetMatchingBlocks()
 
-	opCodes := make([]opCode, 0, len(matching))
+	opCodes := make([]OpCode, 0, len(matching))
 
 	for _, m := range matching {
 		ai, bj, size := m.A, m.B, m.Size
@@ -347,21 +420,21 @@ func (s *sequenceMatcher) getOpcodes() []opCode {
 
 		switch {
 		case i < ai && j < bj:
-			tag = 'r'
+			tag = OpReplace
 		case i < ai:
-			tag = 'd'
+			tag = OpDelete
 		case j < bj:
-			tag = 'i'
+			tag = OpInsert
 		}
 
 		if tag > 0 {
-			opCodes = append(opCodes, opCode{tag, i, ai, j, bj})
+			opCodes = append(opCodes, OpCode{tag, i, ai, j, bj})
 		}
 
 		i, j = ai+size, bj+size
 
 		if size > 0 {
-			opCodes = append(opCodes, opCode{'e', ai, i, bj, j})
+			opCodes = append(opCodes, OpCode{OpEqual, ai, i, bj, j})
 		}
 	}
 
@@ -370,93 +443,113 @@ func (s *sequenceMatcher) getOpcodes() []opCode {
 	return s.opCodes
 }
 
-func (s *sequenceMatcher) ratio() float64 {
-	var la, lb int
+// GetGroupedOpcodes isolates change clusters by eliminating ranges with no changes, returning
+// groups ("hunks") of OpCodes with up to n elements of context around each change. This is what
+// UnifiedDiffLines and ContextDiff are built on, and lets you work with only the changed regions of
+// very large sequences. If a and b are the same, the returned slice is empty.
+func (s *SequenceMatcher[T]) GetGroupedOpcodes(n int) [][]OpCode {
+	if n < 0 {
+		n = 0
+	}
+
+	codes := append([]OpCode(nil), s.GetOpcodes()...)
 
-	if len(s.sequenceA) == 1 && len(s.sequenceB) == 1 {
-		la, lb = len(s.sequenceA[0]), len(s.sequenceB[0])
-	} else {
-		la, lb = len(s.sequenceA), len(s.sequenceB)
+	if len(codes) == 0 {
+		codes = []OpCode{{OpEqual, 0, 1, 0, 1}}
 	}
 
-	matches := 0
-	for _, mb := range s.getMatchingBlocks() {
-		matches += mb.Size
+	// fixup leading and trailing groups if they show no changes
+	if codes[0].Tag == OpEqual {
+		c := codes[0]
+		codes[0] = OpCode{
+			OpEqual, max(c.SeqALo, c.SeqAHi-n), c.SeqAHi, max(c.SeqBLo, c.SeqBHi-n), c.SeqBHi,
+		}
 	}
 
-	return calculateRatio(matches, la+lb)
-}
+	if codes[len(codes)-1].Tag == OpEqual {
+		c := codes[len(codes)-1]
+		codes[len(codes)-1] = OpCode{
+			OpEqual, c.SeqALo, min(c.SeqAHi, c.SeqALo+n), c.SeqBLo, min(c.SeqBHi, c.SeqBLo+n),
+		}
+	}
 
-func (s *sequenceMatcher) quickRatio() float64 {
-	var matches, la, lb int
+	var groups [][]OpCode
 
-	if len(s.sequenceA) == 1 && len(s.sequenceB) == 1 { //nolint:nestif
-		seqA, seqB := s.sequenceA[0], s.sequenceB[0]
-		la, lb = len(seqA), len(seqB)
+	var group []OpCode
 
-		if s.fullBCount == nil {
-			s.fullBCount = map[string]int{}
-			for _, x := range seqB {
-				s.fullBCount[string(x)]++
-			}
+	for _, c := range codes {
+		// end the current group and start a new one whenever there is a large range with no
+		// changes
+		if c.Tag == OpEqual && c.SeqAHi-c.SeqALo > n+n {
+			group = append(
+				group,
+				OpCode{
+					OpEqual,
+					c.SeqALo, min(c.SeqAHi, c.SeqALo+n),
+					c.SeqBLo, min(c.SeqBHi, c.SeqBLo+n),
+				},
+			)
+			groups = append(groups, group)
+			group = nil
+
+			c.SeqALo, c.SeqBLo = max(c.SeqALo, c.SeqAHi-n), max(c.SeqBLo, c.SeqBHi-n)
 		}
 
-		avail := map[string]int{}
-		matches = 0
+		group = append(group, c)
+	}
 
-		for _, x := range seqA {
-			n, ok := avail[string(x)]
-			if !ok {
-				n = s.fullBCount[string(x)]
-			}
+	if len(group) > 0 && !(len(group) == 1 && group[0].Tag == OpEqual) {
+		groups = append(groups, group)
+	}
 
-			avail[string(x)] = n - 1
+	return groups
+}
 
-			if n > 0 {
-				matches++
-			}
-		}
-	} else {
-		la, lb = len(s.sequenceA), len(s.sequenceB)
+// Ratio returns a measure of the sequences' similarity as a float in the range [0, 1]. This is
+// 2.0*M / T where T is the total number of elements in both sequences and M is the number of
+// matches. This is expensive to compute if GetMatchingBlocks or GetOpcodes hasn't already been
+// called, in which case you may want to try QuickRatio or RealQuickRatio first.
+func (s *SequenceMatcher[T]) Ratio() float64 {
+	matches := 0
+	for _, mb := range s.GetMatchingBlocks() {
+		matches += mb.Size
+	}
 
-		if s.fullBCount == nil {
-			s.fullBCount = map[string]int{}
-			for _, x := range s.sequenceB {
-				s.fullBCount[x]++
-			}
+	return calculateRatio(matches, len(s.sequenceA)+len(s.sequenceB))
+}
+
+// QuickRatio returns an upper bound on Ratio relatively quickly.
+func (s *SequenceMatcher[T]) QuickRatio() float64 {
+	if s.fullBCount == nil {
+		s.fullBCount = map[T]int{}
+		for _, x := range s.sequenceB {
+			s.fullBCount[x]++
 		}
+	}
 
-		avail := map[string]int{}
+	avail := map[T]int{}
 
-		matches = 0
+	matches := 0
 
-		for _, x := range s.sequenceA {
-			n, ok := avail[x]
-			if !ok {
-				n = s.fullBCount[x]
-			}
+	for _, x := range s.sequenceA {
+		n, ok := avail[x]
+		if !ok {
+			n = s.fullBCount[x]
+		}
 
-			avail[x] = n - 1
+		avail[x] = n - 1
 
-			if n > 0 {
-				matches++
-			}
+		if n > 0 {
+			matches++
 		}
 	}
 
-	return calculateRatio(matches, la+lb)
+	return calculateRatio(matches, len(s.sequenceA)+len(s.sequenceB))
 }
 
-func (s *sequenceMatcher) realQuickRatio() float64 {
-	var la, lb int
-
-	// different than python because we must have slices of strings, so if slice is len 1
-	// we can just use the length of the zeroith element
-	if len(s.sequenceA) == 1 && len(s.sequenceB) == 1 {
-		la, lb = len(s.sequenceA[0]), len(s.sequenceB[0])
-	} else {
-		la, lb = len(s.sequenceA), len(s.sequenceB)
-	}
+// RealQuickRatio returns an upper bound on Ratio very quickly.
+func (s *SequenceMatcher[T]) RealQuickRatio() float64 {
+	la, lb := len(s.sequenceA), len(s.sequenceB)
 
 	return calculateRatio(min(la, lb), la+lb)
 }
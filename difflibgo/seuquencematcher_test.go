@@ -0,0 +1,733 @@
+package difflibgo_test
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestSequenceMatcher(t *testing.T) {
+	cases := []struct {
+		name                   string
+		a                      []string
+		b                      []string
+		expectedLongestMatch   difflibgo.Match
+		expectedMatchingBlocks []difflibgo.Match
+		expectedOpCodes        []difflibgo.OpCode
+		expectedRatio          float64
+		expectedQuickRatio     float64
+		expectedRealQuickRatio float64
+	}{
+		{
+			name: "simple",
+			a:    []string{"abc", "def", "ghi", "jkl"},
+			b:    []string{"abc", "xyz", "ghi", "jkl", "mno"},
+			expectedLongestMatch: difflibgo.Match{
+				A:    2,
+				B:    2,
+				Size: 2,
+			},
+			expectedMatchingBlocks: []difflibgo.Match{
+				{A: 0, B: 0, Size: 1},
+				{A: 2, B: 2, Size: 2},
+				{A: 4, B: 5, Size: 0},
+			},
+			expectedOpCodes: []difflibgo.OpCode{
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+				{Tag: difflibgo.OpReplace, SeqALo: 1, SeqAHi: 2, SeqBLo: 1, SeqBHi: 2},
+				{Tag: difflibgo.OpEqual, SeqALo: 2, SeqAHi: 4, SeqBLo: 2, SeqBHi: 4},
+				{Tag: difflibgo.OpInsert, SeqALo: 4, SeqAHi: 4, SeqBLo: 4, SeqBHi: 5},
+			},
+			expectedRatio:          2.0 * 3 / 9,
+			expectedQuickRatio:     2.0 * 3 / 9,
+			expectedRealQuickRatio: 2.0 * 4 / 9,
+		},
+		{
+			name: "no-match",
+			a:    []string{"abc"},
+			b:    []string{},
+			expectedLongestMatch: difflibgo.Match{
+				A:    0,
+				B:    0,
+				Size: 0,
+			},
+			expectedMatchingBlocks: []difflibgo.Match{
+				{A: 1, B: 0, Size: 0},
+			},
+			expectedOpCodes: []difflibgo.OpCode{
+				{Tag: difflibgo.OpDelete, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 0},
+			},
+			expectedRatio:          0,
+			expectedQuickRatio:     0,
+			expectedRealQuickRatio: 0,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				s := difflibgo.NewSequenceMatcher(testCase.a, testCase.b)
+
+				actualLongestMatch := s.FindLongestMatch(0, len(testCase.a), 0, len(testCase.b))
+				if actualLongestMatch != testCase.expectedLongestMatch {
+					t.Fatalf(
+						"longest match: actual %+v, expected %+v",
+						actualLongestMatch,
+						testCase.expectedLongestMatch,
+					)
+				}
+
+				actualMatchingBlocks := s.GetMatchingBlocks()
+				if !reflect.DeepEqual(actualMatchingBlocks, testCase.expectedMatchingBlocks) {
+					t.Fatalf(
+						"matching blocks: actual %+v, expected %+v",
+						actualMatchingBlocks,
+						testCase.expectedMatchingBlocks,
+					)
+				}
+
+				actualOpCodes := s.GetOpcodes()
+				if !reflect.DeepEqual(actualOpCodes, testCase.expectedOpCodes) {
+					t.Fatalf(
+						"opcodes: actual %+v, expected %+v",
+						actualOpCodes,
+						testCase.expectedOpCodes,
+					)
+				}
+
+				if s.Ratio() != testCase.expectedRatio {
+					t.Fatalf("ratio: actual %f, expected %f", s.Ratio(), testCase.expectedRatio)
+				}
+
+				if s.QuickRatio() != testCase.expectedQuickRatio {
+					t.Fatalf(
+						"quick ratio: actual %f, expected %f",
+						s.QuickRatio(),
+						testCase.expectedQuickRatio,
+					)
+				}
+
+				if s.RealQuickRatio() != testCase.expectedRealQuickRatio {
+					t.Fatalf(
+						"real quick ratio: actual %f, expected %f",
+						s.RealQuickRatio(),
+						testCase.expectedRealQuickRatio,
+					)
+				}
+			},
+		)
+	}
+}
+
+func TestSequenceMatcherElementTypes(t *testing.T) {
+	t.Run(
+		"bytes",
+		func(t *testing.T) {
+			s := difflibgo.NewSequenceMatcher([]byte("qabxcd"), []byte("abycdf"))
+
+			expected := []difflibgo.OpCode{
+				{Tag: difflibgo.OpDelete, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 0},
+				{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 3, SeqBLo: 0, SeqBHi: 2},
+				{Tag: difflibgo.OpReplace, SeqALo: 3, SeqAHi: 4, SeqBLo: 2, SeqBHi: 3},
+				{Tag: difflibgo.OpEqual, SeqALo: 4, SeqAHi: 6, SeqBLo: 3, SeqBHi: 5},
+				{Tag: difflibgo.OpInsert, SeqALo: 6, SeqAHi: 6, SeqBLo: 5, SeqBHi: 6},
+			}
+
+			actual := s.GetOpcodes()
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("opcodes: actual %+v, expected %+v", actual, expected)
+			}
+		},
+	)
+
+	t.Run(
+		"ints",
+		func(t *testing.T) {
+			s := difflibgo.NewSequenceMatcher([]int{1, 2, 3, 4, 5}, []int{1, 2, 4, 5, 6})
+
+			expected := []difflibgo.Match{
+				{A: 0, B: 0, Size: 2},
+				{A: 3, B: 2, Size: 2},
+				{A: 5, B: 5, Size: 0},
+			}
+
+			actual := s.GetMatchingBlocks()
+			if !reflect.DeepEqual(actual, expected) {
+				t.Fatalf("matching blocks: actual %+v, expected %+v", actual, expected)
+			}
+
+			if s.Ratio() != 0.8 {
+				t.Fatalf("ratio: actual %f, expected %f", s.Ratio(), 0.8)
+			}
+		},
+	)
+}
+
+func TestSequenceMatcherGetGroupedOpcodes(t *testing.T) {
+	a := make([]string, 39)
+	for i := range a {
+		a[i] = strconv.Itoa(i + 1)
+	}
+
+	b := append([]string{"1", "x"}, a[4:22]...)
+	b = append(b, "y")
+	b = append(b, a[23:37]...)
+	b = append(b, "z", "38", "39")
+
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		n        int
+		expected [][]difflibgo.OpCode
+	}{
+		{
+			name:     "no-diff",
+			a:        a,
+			b:        a,
+			n:        3,
+			expected: nil,
+		},
+		{
+			name: "simple",
+			a:    a,
+			b:    b,
+			n:    3,
+			expected: [][]difflibgo.OpCode{
+				{
+					{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+					{Tag: difflibgo.OpReplace, SeqALo: 1, SeqAHi: 4, SeqBLo: 1, SeqBHi: 2},
+					{Tag: difflibgo.OpEqual, SeqALo: 4, SeqAHi: 7, SeqBLo: 2, SeqBHi: 5},
+				},
+				{
+					{Tag: difflibgo.OpEqual, SeqALo: 19, SeqAHi: 22, SeqBLo: 17, SeqBHi: 20},
+					{Tag: difflibgo.OpReplace, SeqALo: 22, SeqAHi: 23, SeqBLo: 20, SeqBHi: 21},
+					{Tag: difflibgo.OpEqual, SeqALo: 23, SeqAHi: 26, SeqBLo: 21, SeqBHi: 24},
+				},
+				{
+					{Tag: difflibgo.OpEqual, SeqALo: 34, SeqAHi: 37, SeqBLo: 32, SeqBHi: 35},
+					{Tag: difflibgo.OpInsert, SeqALo: 37, SeqAHi: 37, SeqBLo: 35, SeqBHi: 36},
+					{Tag: difflibgo.OpEqual, SeqALo: 37, SeqAHi: 39, SeqBLo: 36, SeqBHi: 38},
+				},
+			},
+		},
+		{
+			name: "zero-context",
+			a:    a,
+			b:    b,
+			n:    0,
+			expected: [][]difflibgo.OpCode{
+				{
+					{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 1, SeqBLo: 1, SeqBHi: 1},
+					{Tag: difflibgo.OpReplace, SeqALo: 1, SeqAHi: 4, SeqBLo: 1, SeqBHi: 2},
+					{Tag: difflibgo.OpEqual, SeqALo: 4, SeqAHi: 4, SeqBLo: 2, SeqBHi: 2},
+				},
+				{
+					{Tag: difflibgo.OpEqual, SeqALo: 22, SeqAHi: 22, SeqBLo: 20, SeqBHi: 20},
+					{Tag: difflibgo.OpReplace, SeqALo: 22, SeqAHi: 23, SeqBLo: 20, SeqBHi: 21},
+					{Tag: difflibgo.OpEqual, SeqALo: 23, SeqAHi: 23, SeqBLo: 21, SeqBHi: 21},
+				},
+				{
+					{Tag: difflibgo.OpEqual, SeqALo: 37, SeqAHi: 37, SeqBLo: 35, SeqBHi: 35},
+					{Tag: difflibgo.OpInsert, SeqALo: 37, SeqAHi: 37, SeqBLo: 35, SeqBHi: 36},
+					{Tag: difflibgo.OpEqual, SeqALo: 37, SeqAHi: 37, SeqBLo: 36, SeqBHi: 36},
+				},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				s := difflibgo.NewSequenceMatcher(testCase.a, testCase.b)
+
+				actual := s.GetGroupedOpcodes(testCase.n)
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf("grouped opcodes: actual %+v, expected %+v", actual, testCase.expected)
+				}
+
+				// grouping must not clobber the (cached) ungrouped opcodes
+				opCodes := s.GetOpcodes()
+				if opCodes[0].SeqALo != 0 || opCodes[len(opCodes)-1].SeqAHi != len(testCase.a) {
+					t.Fatalf("opcodes modified by grouping: %+v", opCodes)
+				}
+			},
+		)
+	}
+}
+
+func TestSequenceMatcherIsJunk(t *testing.T) {
+	isSpace := difflibgo.WithMatcherIsJunk(func(c byte) bool { return c == ' ' })
+
+	s := difflibgo.NewSequenceMatcher(
+		[]byte("private Thread currentThread;"),
+		[]byte("private volatile Thread currentThread;"),
+		isSpace,
+	)
+
+	expected := []difflibgo.Match{
+		{A: 0, B: 0, Size: 8},
+		{A: 8, B: 17, Size: 21},
+		{A: 29, B: 38, Size: 0},
+	}
+
+	actual := s.GetMatchingBlocks()
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("matching blocks: actual %+v, expected %+v", actual, expected)
+	}
+
+	// junk can't start a match, so the leading space no longer anchors the longest match
+	for _, testCase := range []struct {
+		opts     []difflibgo.MatcherOption[byte]
+		expected difflibgo.Match
+	}{
+		{
+			expected: difflibgo.Match{A: 0, B: 4, Size: 5},
+		},
+		{
+			opts:     []difflibgo.MatcherOption[byte]{isSpace},
+			expected: difflibgo.Match{A: 1, B: 0, Size: 4},
+		},
+	} {
+		s = difflibgo.NewSequenceMatcher([]byte(" abcd"), []byte("abcd abcd"), testCase.opts...)
+
+		actualMatch := s.FindLongestMatch(0, 5, 0, 9)
+		if actualMatch != testCase.expected {
+			t.Fatalf("longest match: actual %+v, expected %+v", actualMatch, testCase.expected)
+		}
+	}
+}
+
+func TestSequenceMatcherKey(t *testing.T) {
+	maskDigits := func(line string) string {
+		return strings.Map(func(c rune) rune {
+			if c >= '0' && c <= '9' {
+				return '#'
+			}
+
+			return c
+		}, line)
+	}
+
+	a := []string{"uptime is 1 week, 2 days", "5 input errors", "Gi0/1 is up"}
+	b := []string{"uptime is 1 week, 3 days", "5 input errors", "GI0/1 IS UP"}
+
+	cases := []struct {
+		name     string
+		opts     []difflibgo.MatcherOption[string]
+		expected []difflibgo.OpCode
+	}{
+		{
+			name: "no-key",
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpReplace, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+				{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 2, SeqBLo: 1, SeqBHi: 2},
+				{Tag: difflibgo.OpReplace, SeqALo: 2, SeqAHi: 3, SeqBLo: 2, SeqBHi: 3},
+			},
+		},
+		{
+			name: "mask-digits",
+			opts: []difflibgo.MatcherOption[string]{difflibgo.WithMatcherKey(maskDigits)},
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 2, SeqBLo: 0, SeqBHi: 2},
+				{Tag: difflibgo.OpReplace, SeqALo: 2, SeqAHi: 3, SeqBLo: 2, SeqBHi: 3},
+			},
+		},
+		{
+			name: "lowercase",
+			opts: []difflibgo.MatcherOption[string]{difflibgo.WithMatcherKey(strings.ToLower)},
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpReplace, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+				{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 3, SeqBLo: 1, SeqBHi: 3},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				s := difflibgo.NewSequenceMatcher(a, b, testCase.opts...)
+
+				actual := s.GetOpcodes()
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf("actual %+v, expected %+v", actual, testCase.expected)
+				}
+			},
+		)
+	}
+
+	// the key also applies to sequences set later on
+	s := difflibgo.NewSequenceMatcher(nil, b, difflibgo.WithMatcherKey(strings.ToLower))
+	s.SetSeq1([]string{"UPTIME IS 1 WEEK, 3 DAYS", "5 Input Errors", "gi0/1 is up"})
+
+	if ratio := s.Ratio(); ratio != 1 {
+		t.Fatalf("expected ratio 1, got %v", ratio)
+	}
+}
+
+func TestSequenceMatcherAutoJunk(t *testing.T) {
+	a := make([]string, 0, 240)
+	for i := 0; i < 120; i++ {
+		a = append(a, "!", "exit")
+	}
+
+	b := append([]string(nil), a...)
+	b[100] = "interface"
+
+	cases := []struct {
+		name     string
+		opts     []difflibgo.MatcherOption[string]
+		expected []difflibgo.OpCode
+	}{
+		{
+			name: "autojunk",
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 100, SeqBLo: 0, SeqBHi: 100},
+				{Tag: difflibgo.OpReplace, SeqALo: 100, SeqAHi: 240, SeqBLo: 100, SeqBHi: 240},
+			},
+		},
+		{
+			name: "no-autojunk",
+			opts: []difflibgo.MatcherOption[string]{difflibgo.WithMatcherAutoJunk[string](false)},
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+				{Tag: difflibgo.OpInsert, SeqALo: 1, SeqAHi: 1, SeqBLo: 1, SeqBHi: 101},
+				{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 140, SeqBLo: 101, SeqBHi: 240},
+				{Tag: difflibgo.OpDelete, SeqALo: 140, SeqAHi: 240, SeqBLo: 240, SeqBHi: 240},
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.NewSequenceMatcher(a, b, testCase.opts...).GetOpcodes()
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf("opcodes: actual %+v, expected %+v", actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestSequenceMatcherAlgorithms(t *testing.T) {
+	a := make([]string, 0, 240)
+	for i := 0; i < 120; i++ {
+		a = append(a, "!", "exit")
+	}
+
+	b := append([]string(nil), a...)
+	b[100] = "interface"
+
+	stanzasA := []string{
+		"interface Gi1", " no shutdown", "!",
+		"interface Gi2", " no shutdown", "!",
+		"interface Gi3", " mtu 9000", "!",
+	}
+	stanzasB := []string{
+		"interface Gi3", " mtu 9000", "!",
+		"interface Gi2", " shutdown", "!",
+		"interface Gi1", " no shutdown", "!",
+	}
+
+	cases := []struct {
+		name          string
+		algorithm     difflibgo.Algorithm
+		a             []string
+		b             []string
+		expected      []difflibgo.OpCode
+		expectedRatio float64
+	}{
+		{
+			name:      "ratcliff-obershelp-not-minimal",
+			algorithm: difflibgo.AlgorithmRatcliffObershelp,
+			a:         strings.Split("abcabba", ""),
+			b:         strings.Split("cbabac", ""),
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpInsert, SeqALo: 0, SeqAHi: 0, SeqBLo: 0, SeqBHi: 2},
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 2, SeqBLo: 2, SeqBHi: 4},
+				{Tag: difflibgo.OpInsert, SeqALo: 2, SeqAHi: 2, SeqBLo: 4, SeqBHi: 5},
+				{Tag: difflibgo.OpEqual, SeqALo: 2, SeqAHi: 3, SeqBLo: 5, SeqBHi: 6},
+				{Tag: difflibgo.OpDelete, SeqALo: 3, SeqAHi: 7, SeqBLo: 6, SeqBHi: 6},
+			},
+			expectedRatio: 6.0 / 13,
+		},
+		{
+			name:      "myers-minimal",
+			algorithm: difflibgo.AlgorithmMyers,
+			a:         strings.Split("abcabba", ""),
+			b:         strings.Split("cbabac", ""),
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpReplace, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+				{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 2, SeqBLo: 1, SeqBHi: 2},
+				{Tag: difflibgo.OpDelete, SeqALo: 2, SeqAHi: 3, SeqBLo: 2, SeqBHi: 2},
+				{Tag: difflibgo.OpEqual, SeqALo: 3, SeqAHi: 5, SeqBLo: 2, SeqBHi: 4},
+				{Tag: difflibgo.OpDelete, SeqALo: 5, SeqAHi: 6, SeqBLo: 4, SeqBHi: 4},
+				{Tag: difflibgo.OpEqual, SeqALo: 6, SeqAHi: 7, SeqBLo: 4, SeqBHi: 5},
+				{Tag: difflibgo.OpInsert, SeqALo: 7, SeqAHi: 7, SeqBLo: 5, SeqBHi: 6},
+			},
+			expectedRatio: 8.0 / 13,
+		},
+		{
+			name:      "myers-no-junk-heuristic",
+			algorithm: difflibgo.AlgorithmMyers,
+			a:         a,
+			b:         b,
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 100, SeqBLo: 0, SeqBHi: 100},
+				{Tag: difflibgo.OpReplace, SeqALo: 100, SeqAHi: 101, SeqBLo: 100, SeqBHi: 101},
+				{Tag: difflibgo.OpEqual, SeqALo: 101, SeqAHi: 240, SeqBLo: 101, SeqBHi: 240},
+			},
+			expectedRatio: 478.0 / 480,
+		},
+		{
+			name:      "myers-latches-on-boilerplate",
+			algorithm: difflibgo.AlgorithmMyers,
+			a:         stanzasA,
+			b:         stanzasB,
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpReplace, SeqALo: 0, SeqAHi: 2, SeqBLo: 0, SeqBHi: 2},
+				{Tag: difflibgo.OpEqual, SeqALo: 2, SeqAHi: 4, SeqBLo: 2, SeqBHi: 4},
+				{Tag: difflibgo.OpReplace, SeqALo: 4, SeqAHi: 5, SeqBLo: 4, SeqBHi: 5},
+				{Tag: difflibgo.OpEqual, SeqALo: 5, SeqAHi: 6, SeqBLo: 5, SeqBHi: 6},
+				{Tag: difflibgo.OpReplace, SeqALo: 6, SeqAHi: 8, SeqBLo: 6, SeqBHi: 8},
+				{Tag: difflibgo.OpEqual, SeqALo: 8, SeqAHi: 9, SeqBLo: 8, SeqBHi: 9},
+			},
+			expectedRatio: 8.0 / 18,
+		},
+		{
+			name:      "patience-anchors-on-unique-lines",
+			algorithm: difflibgo.AlgorithmPatience,
+			a:         stanzasA,
+			b:         stanzasB,
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpDelete, SeqALo: 0, SeqAHi: 6, SeqBLo: 0, SeqBHi: 0},
+				{Tag: difflibgo.OpEqual, SeqALo: 6, SeqAHi: 8, SeqBLo: 0, SeqBHi: 2},
+				{Tag: difflibgo.OpInsert, SeqALo: 8, SeqAHi: 8, SeqBLo: 2, SeqBHi: 8},
+				{Tag: difflibgo.OpEqual, SeqALo: 8, SeqAHi: 9, SeqBLo: 8, SeqBHi: 9},
+			},
+			expectedRatio: 6.0 / 18,
+		},
+		{
+			name:      "patience-no-unique-lines",
+			algorithm: difflibgo.AlgorithmPatience,
+			a:         strings.Split("abcabba", ""),
+			b:         strings.Split("cbabac", ""),
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpReplace, SeqALo: 0, SeqAHi: 1, SeqBLo: 0, SeqBHi: 1},
+				{Tag: difflibgo.OpEqual, SeqALo: 1, SeqAHi: 2, SeqBLo: 1, SeqBHi: 2},
+				{Tag: difflibgo.OpDelete, SeqALo: 2, SeqAHi: 3, SeqBLo: 2, SeqBHi: 2},
+				{Tag: difflibgo.OpEqual, SeqALo: 3, SeqAHi: 5, SeqBLo: 2, SeqBHi: 4},
+				{Tag: difflibgo.OpDelete, SeqALo: 5, SeqAHi: 6, SeqBLo: 4, SeqBHi: 4},
+				{Tag: difflibgo.OpEqual, SeqALo: 6, SeqAHi: 7, SeqBLo: 4, SeqBHi: 5},
+				{Tag: difflibgo.OpInsert, SeqALo: 7, SeqAHi: 7, SeqBLo: 5, SeqBHi: 6},
+			},
+			expectedRatio: 8.0 / 13,
+		},
+		{
+			name:      "histogram-anchors-on-rare-lines",
+			algorithm: difflibgo.AlgorithmHistogram,
+			a:         stanzasA,
+			b:         stanzasB,
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpDelete, SeqALo: 0, SeqAHi: 6, SeqBLo: 0, SeqBHi: 0},
+				{Tag: difflibgo.OpEqual, SeqALo: 6, SeqAHi: 8, SeqBLo: 0, SeqBHi: 2},
+				{Tag: difflibgo.OpInsert, SeqALo: 8, SeqAHi: 8, SeqBLo: 2, SeqBHi: 8},
+				{Tag: difflibgo.OpEqual, SeqALo: 8, SeqAHi: 9, SeqBLo: 8, SeqBHi: 9},
+			},
+			expectedRatio: 6.0 / 18,
+		},
+		{
+			name:      "histogram-common-lines",
+			algorithm: difflibgo.AlgorithmHistogram,
+			a:         a,
+			b:         b,
+			expected: []difflibgo.OpCode{
+				{Tag: difflibgo.OpEqual, SeqALo: 0, SeqAHi: 100, SeqBLo: 0, SeqBHi: 100},
+				{Tag: difflibgo.OpReplace, SeqALo: 100, SeqAHi: 101, SeqBLo: 100, SeqBHi: 101},
+				{Tag: difflibgo.OpEqual, SeqALo: 101, SeqAHi: 240, SeqBLo: 101, SeqBHi: 240},
+			},
+			expectedRatio: 478.0 / 480,
+		},
+		{
+			name:          "myers-empty",
+			algorithm:     difflibgo.AlgorithmMyers,
+			expected:      []difflibgo.OpCode{},
+			expectedRatio: 1,
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				s := difflibgo.NewSequenceMatcher(
+					testCase.a,
+					testCase.b,
+					difflibgo.WithMatcherAlgorithm[string](testCase.algorithm),
+				)
+
+				actual := s.GetOpcodes()
+				if !reflect.DeepEqual(actual, testCase.expected) {
+					t.Fatalf("opcodes: actual %+v, expected %+v", actual, testCase.expected)
+				}
+
+				if actualRatio := s.Ratio(); actualRatio != testCase.expectedRatio {
+					t.Fatalf("ratio: actual %v, expected %v", actualRatio, testCase.expectedRatio)
+				}
+			},
+		)
+	}
+}
+
+func TestSequenceMatcherManyMatchingBlocks(t *testing.T) {
+	// every other line changed, each longest match is a single line and leaves the whole rest of
+	// the sequences to match, thousands of regions deep
+	n := 2000
+
+	a, b := make([]string, n), make([]string, n)
+	expected := make([]difflibgo.Match, 0, n/2+1)
+
+	for i := 0; i < n; i++ {
+		a[i] = "route " + strconv.Itoa(i)
+		b[i] = a[i]
+
+		if i%2 == 1 {
+			b[i] = "changed " + strconv.Itoa(i)
+		} else {
+			expected = append(expected, difflibgo.Match{A: i, B: i, Size: 1})
+		}
+	}
+
+	expected = append(expected, difflibgo.Match{A: n, B: n})
+
+	for _, algorithm := range []difflibgo.Algorithm{
+		difflibgo.AlgorithmRatcliffObershelp,
+		difflibgo.AlgorithmMyers,
+		difflibgo.AlgorithmPatience,
+		difflibgo.AlgorithmHistogram,
+	} {
+		actual := difflibgo.NewSequenceMatcher(
+			a, b, difflibgo.WithMatcherAlgorithm[string](algorithm),
+		).GetMatchingBlocks()
+
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("algorithm %d: matching blocks do not match expected", algorithm)
+		}
+	}
+}
+
+func TestSequenceMatcherHistogramLargeRegions(t *testing.T) {
+	// large enough that histogram diff splits it on its unique lines first, it must still find
+	// all the (single line) changes
+	a, b := routeTable(20000)
+
+	expected := difflibgo.NewSequenceMatcher(
+		a, b, difflibgo.WithMatcherAlgorithm[string](difflibgo.AlgorithmMyers),
+	).GetOpcodes()
+
+	actual := difflibgo.NewSequenceMatcher(
+		a, b, difflibgo.WithMatcherAlgorithm[string](difflibgo.AlgorithmHistogram),
+	).GetOpcodes()
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf("histogram opcodes do not match the myers opcodes")
+	}
+}
+
+// routeTable returns a route table of n lines, and a copy of it with every hundredth line
+// changed.
+func routeTable(n int) (a, b []string) {
+	a, b = make([]string, n), make([]string, n)
+
+	for i := 0; i < n; i++ {
+		a[i] = "10." + strconv.Itoa(i/65536%256) + "." + strconv.Itoa(i/256%256) + "." +
+			strconv.Itoa(i%256) + "/32 via 192.0.2." + strconv.Itoa(i%4)
+		b[i] = a[i]
+
+		if i%100 == 50 {
+			b[i] += " [changed]"
+		}
+	}
+
+	return a, b
+}
+
+func BenchmarkSequenceMatcherGetOpcodes(b *testing.B) {
+	seqA, seqB := routeTable(20000)
+
+	for _, bm := range []struct {
+		name      string
+		algorithm difflibgo.Algorithm
+	}{
+		{"ratcliff-obershelp", difflibgo.AlgorithmRatcliffObershelp},
+		{"myers", difflibgo.AlgorithmMyers},
+		{"patience", difflibgo.AlgorithmPatience},
+		{"histogram", difflibgo.AlgorithmHistogram},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				difflibgo.NewSequenceMatcher(
+					seqA, seqB, difflibgo.WithMatcherAlgorithm[string](bm.algorithm),
+				).GetOpcodes()
+			}
+		})
+	}
+}
+
+func BenchmarkSequenceMatcherRepeatedLines(b *testing.B) {
+	// a network device config, lots of "!" and "exit" lines that the autojunk heuristic would
+	// normally throw out
+	seqA := make([]string, 0, 1800)
+	for i := 0; i < 600; i++ {
+		seqA = append(seqA, "interface Gi"+strconv.Itoa(i), "!", "exit")
+	}
+
+	seqB := append([]string(nil), seqA...)
+	for i := 0; i < len(seqB); i += 90 {
+		seqB[i] += " [changed]"
+	}
+
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		difflibgo.NewSequenceMatcher(
+			seqA, seqB, difflibgo.WithMatcherAutoJunk[string](false),
+		).GetOpcodes()
+	}
+}
+
+func BenchmarkSequenceMatcherScaling(b *testing.B) {
+	// the time taken should grow roughly linearly with the size of the (mostly identical) inputs
+	for _, algorithm := range []struct {
+		name      string
+		algorithm difflibgo.Algorithm
+	}{
+		{"myers", difflibgo.AlgorithmMyers},
+		{"patience", difflibgo.AlgorithmPatience},
+		{"histogram", difflibgo.AlgorithmHistogram},
+	} {
+		for _, n := range []int{20000, 40000, 80000} {
+			seqA, seqB := routeTable(n)
+
+			b.Run(algorithm.name+"-"+strconv.Itoa(n), func(b *testing.B) {
+				b.ReportAllocs()
+
+				for i := 0; i < b.N; i++ {
+					difflibgo.NewSequenceMatcher(
+						seqA, seqB, difflibgo.WithMatcherAlgorithm[string](algorithm.algorithm),
+					).GetOpcodes()
+				}
+			})
+		}
+	}
+}
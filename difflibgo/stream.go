@@ -0,0 +1,340 @@
+package difflibgo
+
+import (
+	"bufio"
+	"io"
+	"math"
+)
+
+// Format is an output format of DiffReaders.
+type Format int
+
+const (
+	// FormatUnified is the unified diff format, see UnifiedDiffLines.
+	FormatUnified Format = iota
+	// FormatContext is the context diff format, see ContextDiff.
+	FormatContext
+	// FormatNdiff is the Differ ("ndiff") format, see Ndiff.
+	FormatNdiff
+	// FormatWordDiff is the word diff format, see WordDiff.
+	FormatWordDiff
+)
+
+// ReadLines reads all of r and returns its lines, each keeping its trailing newline. The lines
+// are split the way DiffReaders splits its inputs -- on "\n" only, which is what diff and patch
+// take a line to be, unlike SplitLines.
+func ReadLines(r io.Reader) ([]string, error) {
+	reader := newLineReader(r)
+
+	lines := reader.read(nil, math.MaxInt)
+
+	return lines, reader.error()
+}
+
+// lineReader reads lines, terminators and all, from a reader one at a time.
+type lineReader struct {
+	r   *bufio.Reader
+	err error
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{r: bufio.NewReader(r)}
+}
+
+// next returns the next line, ok is false once there are no more lines or reading failed -- in
+// which case err is set.
+func (l *lineReader) next() (line string, ok bool) {
+	if l.err != nil {
+		return "", false
+	}
+
+	line, err := l.r.ReadString('\n')
+	if err != nil {
+		if err != io.EOF {
+			l.err = err
+
+			return "", false
+		}
+
+		l.err = io.EOF
+	}
+
+	return line, line != ""
+}
+
+// read appends up to n more lines to lines.
+func (l *lineReader) read(lines []string, n int) []string {
+	for ; n > 0; n-- {
+		line, ok := l.next()
+		if !ok {
+			break
+		}
+
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// done returns true once there are no more lines to read.
+func (l *lineReader) done() bool {
+	return l.err != nil
+}
+
+func (l *lineReader) error() error {
+	if l.err == io.EOF {
+		return nil
+	}
+
+	return l.err
+}
+
+func writeLines(w *bufio.Writer, lines ...string) error {
+	for _, line := range lines {
+		if _, err := w.WriteString(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// streamWindow is the number of lines DiffReaders reads from each input at a time, before it
+// writes out the diff up to the last run of lines the inputs have in common.
+const streamWindow = 4096
+
+// DiffReaders compares the lines read from a and b and writes the diff to w, returning true if
+// a and b differ (bar the differences the whitespace options ignore). The output is a unified
+// diff by default, see WithFormat for the other formats; all the options of the respective diff
+// functions apply.
+//
+// Unlike the other diff functions, DiffReaders does not need the inputs or the diff in memory --
+// the lines the two inputs have in common are read in lock step and dropped (bar the few needed for
+// context), the lines that differ are read a window of a few thousand lines at a time, and once the
+// inputs are back in step (there is a run of common lines long enough to end a hunk) the diff up to
+// there is written out and the lines before it dropped. So diffing huge files only ever holds a
+// window of lines in memory -- or, where the inputs differ for longer than that, up to about twice
+// the differing lines. As a consequence the lines are matched up a stretch at a time: common
+// leading lines are always matched up with one another, which is what diff and git do, but python's
+// algorithm (the default) may not, and the diffs of long, differing stretches may match up lines
+// differently than the other diff functions would.
+func DiffReaders(a, b io.Reader, w io.Writer, opts ...Option) (bool, error) {
+	o := newOptions(opts...)
+	// lines are read terminators and all, so an unterminated last line is missing its newline
+	o.lineEndings = true
+
+	s := &streamDiff{
+		o:       o,
+		opts:    opts,
+		w:       bufio.NewWriter(w),
+		readerA: newLineReader(a),
+		readerB: newLineReader(b),
+		key:     o.key(),
+	}
+
+	err := s.run()
+
+	return s.different, err
+}
+
+// streamDiff is the state of DiffReaders: a and b are the lines read but not yet diffed, starting
+// at the (zero based) line offsetA and offsetB of the inputs, the first common of which are the
+// common lines kept as leading context.
+type streamDiff struct {
+	o                *options
+	opts             []Option
+	w                *bufio.Writer
+	readerA, readerB *lineReader
+	key              func(string) string
+	a, b             []string
+	offsetA, offsetB int
+	common           int
+	different        bool
+}
+
+// run diffs the inputs a window at a time, until both are read.
+func (s *streamDiff) run() error {
+	n := streamWindow
+
+	for {
+		if len(s.a) == s.common && len(s.b) == s.common {
+			if err := s.skipCommon(); err != nil {
+				return err
+			}
+		}
+
+		s.a, s.b = s.readerA.read(s.a, n), s.readerB.read(s.b, n)
+
+		if err := s.readerA.error(); err != nil {
+			return err
+		}
+
+		if err := s.readerB.error(); err != nil {
+			return err
+		}
+
+		if s.readerA.done() && s.readerB.done() {
+			return s.flush(len(s.a), len(s.b))
+		}
+
+		aCut, bCut, ok := s.cut()
+		if !ok {
+			// not back in step yet, read on -- as much again as there is, so that a long stretch
+			// of differing lines is only matched up a few times over
+			n = max(len(s.a), len(s.b))
+
+			continue
+		}
+
+		if err := s.flush(aCut, bCut); err != nil {
+			return err
+		}
+
+		// copied so that the lines already diffed can be garbage collected
+		s.a = append([]string(nil), s.a[aCut:]...)
+		s.b = append([]string(nil), s.b[bCut:]...)
+		s.offsetA, s.offsetB = s.offsetA+aCut, s.offsetB+bCut
+		s.common = s.leadingContext()
+		n = streamWindow
+	}
+}
+
+// leadingContext returns the number of common lines kept as the leading context of the next
+// hunk -- none for the ndiff and word diff formats, which write the common lines out as they go.
+func (s *streamDiff) leadingContext() int {
+	if s.o.format == FormatNdiff || s.o.format == FormatWordDiff {
+		return 0
+	}
+
+	return s.o.context
+}
+
+// skipCommon reads the lines the inputs continue with in common in lock step, up to the first
+// lines that differ (which are kept). The common lines are written out straight away for the
+// ndiff and word diff formats, the others only keep the last few as leading context.
+func (s *streamDiff) skipCommon() error {
+	for {
+		lineA, okA := s.readerA.next()
+		lineB, okB := s.readerB.next()
+
+		if !okA || !okB || lineA != lineB && (s.key == nil || s.key(lineA) != s.key(lineB)) {
+			if okA {
+				s.a = append(s.a, lineA)
+			}
+
+			if okB {
+				s.b = append(s.b, lineB)
+			}
+
+			return nil
+		}
+
+		switch s.o.format {
+		case FormatNdiff:
+			if err := writeTerminated(s.w, diffEqual+lineA, s.o); err != nil {
+				return err
+			}
+		case FormatWordDiff:
+			if err := writeTerminated(s.w, lineA, s.o); err != nil {
+				return err
+			}
+		default:
+			s.a, s.b = append(s.a, lineA), append(s.b, lineB)
+
+			if len(s.a) > s.o.context {
+				s.a, s.b = s.a[1:], s.b[1:]
+				s.offsetA, s.offsetB = s.offsetA+1, s.offsetB+1
+			}
+
+			s.common = len(s.a)
+		}
+	}
+}
+
+// cut returns where to cut the lines read in two, so that the diff of a[:aCut] and b[:bCut] can
+// be written out, and the rest diffed once more lines are read. The cut is made at the end of the
+// last run of common lines long enough to separate two hunks (any common line will do for the
+// ndiff and word diff formats), bar the lines kept as leading context. ok is false if there is
+// no such run.
+func (s *streamDiff) cut() (aCut, bCut int, ok bool) {
+	n := s.leadingContext()
+
+	opCodes := NewSequenceMatcher(s.a, s.b, s.o.matcherOptions()...).GetOpcodes()
+
+	for idx := len(opCodes) - 1; idx >= 0; idx-- {
+		if c := opCodes[idx]; c.Tag == OpEqual && c.SeqAHi-c.SeqALo > 2*n {
+			return c.SeqAHi - n, c.SeqBHi - n, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// flush writes out the diff of a[:aEnd] and b[:bEnd].
+func (s *streamDiff) flush(aEnd, bEnd int) error {
+	a, b := s.a[:aEnd], s.b[:bEnd]
+
+	var err error
+
+	switch s.o.format {
+	case FormatNdiff:
+		err = ndiffer(s.opts...).CompareFunc(a, b, func(diffLine DiffLine) error {
+			s.different = s.different ||
+				diffLine.Kind != LineEqual && diffLine.Kind != LineIgnored
+
+			return writeTerminated(s.w, diffLine.String(), s.o)
+		})
+	case FormatWordDiff:
+		err = wordDiffer(s.opts...).compareWords(a, b, func(wordDiffLine WordDiffLine) error {
+			s.different = s.different || wordDiffLine.Changed()
+
+			return writeTerminated(s.w, wordDiffLine.String(), s.o)
+		})
+	default:
+		err = s.writeHunks(a, b)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	// the hunks written are complete, no need to hold them back
+	return s.w.Flush()
+}
+
+// writeTerminated writes line, terminated with the line terminator if it has no newline -- the last
+// line of an input may have none, and would run into the next one.
+func writeTerminated(w *bufio.Writer, line string, o *options) error {
+	if !hasLineEnding(line) {
+		line += o.lineTerm
+	}
+
+	_, err := w.WriteString(line)
+
+	return err
+}
+
+// writeHunks writes the hunks of the diff of a and b, preceded by the header if they are the
+// first.
+func (s *streamDiff) writeHunks(a, b []string) error {
+	header, hunk := unifiedHeader, unifiedHunk
+	if s.o.format == FormatContext {
+		header, hunk = contextHeader, contextHunk
+	}
+
+	for _, group := range s.o.groupedOpcodes(a, b) {
+		if !s.different {
+			if err := writeLines(s.w, header(s.o)...); err != nil {
+				return err
+			}
+
+			s.different = true
+		}
+
+		if err := writeLines(s.w, hunk(a, b, group, s.offsetA, s.offsetB, s.o)...); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,322 @@
+package difflibgo_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestDiffReaders(t *testing.T) {
+	var long []string
+	for i := 0; i < 100; i++ {
+		long = append(long, "line "+strconv.Itoa(i)+"\n")
+	}
+
+	longChanged := append([]string(nil), long...)
+	longChanged[50] = "changed\n"
+	longChanged = append(longChanged, "appended\n")
+
+	longReindented := make([]string, len(long))
+	for idx, line := range long {
+		longReindented[idx] = "  " + strings.ReplaceAll(line, " ", "\t")
+	}
+
+	// several windows worth of lines, changed here and there
+	huge, hugeChanged := hugeInputs(20000)
+
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		opts     []difflibgo.Option
+		same     bool
+		expected func(a, b []string, opts ...difflibgo.Option) []string
+	}{
+		{
+			name:     "unified-no-diff",
+			a:        long,
+			b:        long,
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name: "unified-long-common-prefix",
+			a:    long,
+			b:    longChanged,
+			opts: []difflibgo.Option{
+				difflibgo.WithFromFile("a.log", ""),
+				difflibgo.WithToFile("b.log", ""),
+			},
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name:     "unified-first-line-changed",
+			a:        []string{"one\n", "two\n", "three\n"},
+			b:        []string{"1\n", "two\n", "three\n", "four\n"},
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name:     "unified-zero-context",
+			a:        long,
+			b:        longChanged,
+			opts:     []difflibgo.Option{difflibgo.WithContext(0)},
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name:     "unified-a-empty",
+			a:        nil,
+			b:        []string{"one\n", "two\n"},
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name:     "context",
+			a:        long,
+			b:        longChanged,
+			opts:     []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatContext)},
+			expected: difflibgo.ContextDiff,
+		},
+		{
+			name: "ndiff",
+			a:    []string{"one\n", "two\n", "three\n"},
+			b:    []string{"one\n", "tree\n", "emu\n"},
+			opts: []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatNdiff)},
+			expected: func(a, b []string, _ ...difflibgo.Option) []string {
+				return difflibgo.Ndiff(a, b)
+			},
+		},
+		{
+			name:     "unified-ignore-all-space",
+			a:        longReindented,
+			b:        longChanged,
+			opts:     []difflibgo.Option{difflibgo.WithIgnoreAllSpace(true)},
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name:     "unified-ignore-all-space-same",
+			a:        long,
+			b:        longReindented,
+			opts:     []difflibgo.Option{difflibgo.WithIgnoreAllSpace(true)},
+			same:     true,
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name: "ndiff-ignore-space-change-same",
+			a:    []string{"one\n", "two  2\n", "three\n"},
+			b:    []string{"one\n", "two 2\n", "three  \n"},
+			opts: []difflibgo.Option{
+				difflibgo.WithFormat(difflibgo.FormatNdiff),
+				difflibgo.WithIgnoreSpaceChange(true),
+			},
+			same:     true,
+			expected: difflibgo.Ndiff,
+		},
+		{
+			name:     "unified-no-newline-at-end",
+			a:        long[:len(long)-1],
+			b:        append(append([]string(nil), long[:len(long)-1]...), "line 99"),
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name: "ndiff-no-newline-at-end",
+			a:    []string{"one\n", "two"},
+			b:    []string{"one\n", "2\n", "three"},
+			opts: []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatNdiff)},
+			expected: func(_, _ []string, _ ...difflibgo.Option) []string {
+				return []string{"  one\n", "- two\n", "+ 2\n", "+ three\n"}
+			},
+		},
+		{
+			name: "ndiff-form-feed-is-not-a-newline",
+			a:    []string{"x\n", "abc\f"},
+			b:    []string{"x\n", "abd\f"},
+			opts: []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatNdiff)},
+			expected: func(_, _ []string, _ ...difflibgo.Option) []string {
+				return []string{"  x\n", "- abc\f\n", "?   ^\n", "+ abd\f\n", "?   ^\n"}
+			},
+		},
+		{
+			name:     "word-diff",
+			a:        long,
+			b:        longChanged,
+			opts:     []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatWordDiff)},
+			expected: difflibgo.WordDiff,
+		},
+		{
+			name: "word-diff-no-newline-at-end",
+			a:    []string{"one\n", "two three four"},
+			b:    []string{"one\n", "two 3 four"},
+			opts: []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatWordDiff)},
+			expected: func(_, _ []string, _ ...difflibgo.Option) []string {
+				return []string{"one\n", "two [-three-]{+3+} four\n"}
+			},
+		},
+		{
+			name:     "unified-windows",
+			a:        huge,
+			b:        hugeChanged,
+			expected: difflibgo.UnifiedDiffLines,
+		},
+		{
+			name:     "context-windows",
+			a:        huge,
+			b:        hugeChanged,
+			opts:     []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatContext)},
+			expected: difflibgo.ContextDiff,
+		},
+		{
+			name: "ndiff-windows",
+			a:    huge,
+			b:    hugeChanged,
+			opts: []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatNdiff)},
+			expected: func(a, b []string, _ ...difflibgo.Option) []string {
+				return difflibgo.Ndiff(a, b)
+			},
+		},
+		{
+			name:     "word-diff-windows",
+			a:        huge,
+			b:        hugeChanged,
+			opts:     []difflibgo.Option{difflibgo.WithFormat(difflibgo.FormatWordDiff)},
+			expected: difflibgo.WordDiff,
+		},
+		{
+			name: "unified-normalize-line-endings-same",
+			a:    []string{"one\r\n", "two\r\n"},
+			b:    []string{"one\n", "two\n"},
+			opts: []difflibgo.Option{difflibgo.WithNormalizeLineEndings(true)},
+			same: true,
+			expected: func(_, _ []string, _ ...difflibgo.Option) []string {
+				return nil
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				var w bytes.Buffer
+
+				different, err := difflibgo.DiffReaders(
+					strings.NewReader(strings.Join(testCase.a, "")),
+					strings.NewReader(strings.Join(testCase.b, "")),
+					&w,
+					testCase.opts...,
+				)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				expectedDifferent := !testCase.same &&
+					strings.Join(testCase.a, "") != strings.Join(testCase.b, "")
+				if different != expectedDifferent {
+					t.Fatalf("expected different to be %v", expectedDifferent)
+				}
+
+				expected := testCase.expected(testCase.a, testCase.b, testCase.opts...)
+
+				if w.String() != strings.Join(expected, "") {
+					failOutput(t, strings.SplitAfter(w.String(), "\n"), expected)
+				}
+			},
+		)
+	}
+}
+
+// hugeInputs returns n lines, and a copy of them with a line changed every thousand lines, a
+// stretch of lines inserted and a stretch deleted.
+func hugeInputs(n int) (a, b []string) {
+	for i := 0; i < n; i++ {
+		a = append(a, "10.0."+strconv.Itoa(i/256)+"."+strconv.Itoa(i%256)+"/32 via 192.0.2.1\n")
+	}
+
+	b = append(b, a[:n/2]...)
+
+	for i := 0; i < 100; i++ {
+		b = append(b, "inserted "+strconv.Itoa(i)+"\n")
+	}
+
+	b = append(b, a[n/2:n-n/4]...)
+	b = append(b, a[n-n/4+100:]...)
+
+	for i := 500; i < len(b); i += 1000 {
+		b[i] = "changed " + strconv.Itoa(i) + "\n"
+	}
+
+	return a, b
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestDiffReadersErrors(t *testing.T) {
+	readErr := errors.New("connection reset")
+
+	_, err := difflibgo.DiffReaders(
+		iotest.ErrReader(readErr),
+		strings.NewReader("one\n"),
+		&bytes.Buffer{},
+	)
+	if !errors.Is(err, readErr) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+
+	_, err = difflibgo.DiffReaders(
+		strings.NewReader("one\n"),
+		strings.NewReader("two\n"),
+		failingWriter{},
+	)
+	if err == nil || err.Error() != "disk full" {
+		t.Fatalf("expected write error, got %v", err)
+	}
+}
+
+func TestDiffReadersWritesAsItGoes(t *testing.T) {
+	a, b := hugeInputs(20000)
+	readErr := errors.New("connection reset")
+
+	var w bytes.Buffer
+
+	// a fails after its last line, by when the hunks before the end must have been written out
+	_, err := difflibgo.DiffReaders(
+		io.MultiReader(strings.NewReader(strings.Join(a, "")), iotest.ErrReader(readErr)),
+		strings.NewReader(strings.Join(b, "")),
+		&w,
+	)
+	if !errors.Is(err, readErr) {
+		t.Fatalf("expected read error, got %v", err)
+	}
+
+	expected := strings.Join(difflibgo.UnifiedDiffLines(a, b), "")
+
+	if !strings.Contains(w.String(), "@@") || !strings.HasPrefix(expected, w.String()) {
+		t.Fatalf("expected the diff up to the read error, got:\n%s", w.String())
+	}
+}
+
+func TestReadLines(t *testing.T) {
+	actual, err := difflibgo.ReadLines(strings.NewReader("one\r\ntwo\fthree\n\nfour"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// split on newlines only, as DiffReaders does
+	expected := []string{"one\r\n", "two\fthree\n", "\n", "four"}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"actual and expected do not match...\nactual:   %q\nexpected: %q",
+			actual,
+			expected,
+		)
+	}
+}
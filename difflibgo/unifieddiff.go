@@ -1,41 +1,41 @@
 package difflibgo
 
 import (
+	"fmt"
 	"strings"
 )
 
-func getDiffLines(a, b string) []string {
-	return Compare(
-		strings.Split(a, "\n"),
-		strings.Split(b, "\n"),
-	)
+func getDiffLines(a, b string, opts ...Option) []string {
+	return NewDiffer(opts...).Compare(SplitLines(a, false), SplitLines(b, false))
 }
 
-// UnifiedDiff accepts a and b which can be strings, bytes, or something you can marshal to json
-// and returns a python difflib style diff string or an error (if there is a marshall error).
-func UnifiedDiff(a, b string) string {
-	return strings.Join(getDiffLines(a, b), "\n")
+// UnifiedDiff accepts a and b strings, splits them into lines (see SplitLines) and returns the
+// python difflib Differ style ("ndiff") diff of the two as a single string. Despite its name this
+// is *not* a unified diff, see UnifiedDiffLines if you want the "---/+++/@@" format. The options
+// are those of NewDiffer.
+func UnifiedDiff(a, b string, opts ...Option) string {
+	return strings.Join(getDiffLines(a, b, opts...), "\n")
 }
 
 // UnifiedDiffColorized is the same as UnifiedDiff but instead of the diff symbols (+, -, ?) the
 // line is rewritten with green, red, yellow (respectively) colorization.
-func UnifiedDiffColorized(a, b string) string {
-	diffLines := getDiffLines(a, b)
+func UnifiedDiffColorized(a, b string, opts ...Option) string {
+	diffLines := NewDiffer(opts...).CompareStructured(SplitLines(a, false), SplitLines(b, false))
 
 	unifiedDiffLines := make([]string, len(diffLines))
 
 	for idx, line := range diffLines {
 		var diffLine string
 
-		switch line[:2] {
-		case diffUnknown:
-			diffLine = yellow + line[2:] + end
-		case diffSubtraction:
-			diffLine = red + line[2:] + end
-		case diffAddition:
-			diffLine = green + line[2:] + end
+		switch line.Kind {
+		case LineHint:
+			diffLine = yellow + line.Text + "\n" + end
+		case LineDelete:
+			diffLine = red + line.Text + end
+		case LineInsert:
+			diffLine = green + line.Text + end
 		default:
-			diffLine = line[2:]
+			diffLine = line.Text
 		}
 
 		unifiedDiffLines[idx] = diffLine
@@ -43,3 +43,92 @@ func UnifiedDiffColorized(a, b string) string {
 
 	return strings.Join(unifiedDiffLines, "\n")
 }
+
+func fileHeader(marker, name, date, lineTerm string) string {
+	if date != "" {
+		date = "\t" + date
+	}
+
+	return fmt.Sprintf("%s %s%s%s", marker, name, date, lineTerm)
+}
+
+func formatRangeUnified(start, stop int) string {
+	// lines start numbering with one
+	beginning := start + 1
+	length := stop - start
+
+	if length == 1 {
+		return fmt.Sprintf("%d", beginning)
+	}
+
+	if length == 0 {
+		// empty ranges begin at line just before the range
+		beginning--
+	}
+
+	return fmt.Sprintf("%d,%d", beginning, length)
+}
+
+// UnifiedDiffLines compares a and b (slices of lines) and returns the delta as a unified diff --
+// this is a port of python's difflib.unified_diff. Unified diffs show only the changed lines plus
+// a few lines of context (see WithContext), with "---", "+++" and "@@" control lines. The control
+// lines are terminated with the line terminator (see WithLineTerm), the content lines are output
+// as is -- so if you want output that `patch` or `git apply` accept, the lines of a and b should
+// retain their trailing newlines. If a and b are the same, the returned slice is empty.
+func UnifiedDiffLines(a, b []string, opts ...Option) []string {
+	o := newOptions(opts...)
+	o.lineEndings = hasLineEndings(a, b)
+
+	var diffLines []string
+
+	for _, group := range o.groupedOpcodes(a, b) {
+		if diffLines == nil {
+			diffLines = unifiedHeader(o)
+		}
+
+		diffLines = append(diffLines, unifiedHunk(a, b, group, 0, 0, o)...)
+	}
+
+	return diffLines
+}
+
+func unifiedHeader(o *options) []string {
+	return []string{
+		fileHeader("---", o.fromFile, o.fromFileDate, o.lineTerm),
+		fileHeader("+++", o.toFile, o.toFileDate, o.lineTerm),
+	}
+}
+
+// unifiedHunk returns the lines of the unified diff hunk for group; offsetA and offsetB are added
+// to the line numbers in the "@@" line, for when a and b are windows of the sequences being
+// compared.
+func unifiedHunk(a, b []string, group []OpCode, offsetA, offsetB int, o *options) []string {
+	first, last := group[0], group[len(group)-1]
+
+	hunkLines := []string{
+		fmt.Sprintf(
+			"@@ -%s +%s @@%s",
+			formatRangeUnified(offsetA+first.SeqALo, offsetA+last.SeqAHi),
+			formatRangeUnified(offsetB+first.SeqBLo, offsetB+last.SeqBHi),
+			o.lineTerm,
+		),
+	}
+
+	for _, c := range group {
+		if c.Tag == OpEqual {
+			hunkLines = appendHunkLines(hunkLines, " ", a, c.SeqALo, c.SeqAHi, o)
+
+			continue
+		}
+
+		if c.Tag == OpReplace || c.Tag == OpDelete {
+			hunkLines = appendHunkLines(hunkLines, "-", a, c.SeqALo, c.SeqAHi, o)
+		}
+
+		if c.Tag == OpReplace || c.Tag == OpInsert {
+			hunkLines = appendHunkLines(hunkLines, "+", b, c.SeqBLo, c.SeqBHi, o)
+		}
+	}
+
+	return hunkLines
+}
@@ -1,6 +1,7 @@
 package difflibgo_test
 
 import (
+	"regexp"
 	"strings"
 	"testing"
 
@@ -12,21 +13,20 @@ func TestUnifiedDiff(t *testing.T) {
 		name     string
 		a        string
 		b        string
+		opts     []difflibgo.Option
 		expected string
 	}{
 		{
-			name: "empty-a-string",
-			a:    ``,
-			b:    `foo`,
-			expected: `- 
-+ foo`,
+			name:     "empty-a-string",
+			a:        ``,
+			b:        `foo`,
+			expected: `+ foo`,
 		},
 		{
-			name: "empty-b-string",
-			a:    `foo`,
-			b:    ``,
-			expected: `- foo
-+ `,
+			name:     "empty-b-string",
+			a:        `foo`,
+			b:        ``,
+			expected: `- foo`,
 		},
 		{
 			name: "simple-no-diff",
@@ -55,18 +55,49 @@ z123
 xyz`,
 			expected: `  abc
 - defq
-- 123
+?    -
+
 + def
+- 123
 + z123
+? +
+
   xyz`,
 		},
+		{
+			name: "mask-and-ignore-matching",
+			a: `GigabitEthernet0/1 is up, line protocol is up
+  Last input 00:00:12, output 00:00:01
+  5 minute input rate 1000 bits/sec
+  MTU 1500 bytes`,
+			b: `GigabitEthernet0/1 is up, line protocol is down
+  Last input 00:03:40, output never
+  5 minute input rate 2000 bits/sec
+  MTU 1500 bytes`,
+			opts: []difflibgo.Option{
+				difflibgo.WithMaskMatching(
+					"<time>",
+					regexp.MustCompile(`\d\d:\d\d:\d\d|never`),
+				),
+				difflibgo.WithIgnoreMatching(regexp.MustCompile(`rate \d+ bits/sec`)),
+			},
+			expected: `- GigabitEthernet0/1 is up, line protocol is up
+?                                            ^^
+
++ GigabitEthernet0/1 is up, line protocol is down
+?                                            ^^^^
+
+    Last input 00:00:12, output 00:00:01
+    5 minute input rate 1000 bits/sec
+    MTU 1500 bytes`,
+		},
 	}
 
 	for _, testCase := range cases {
 		t.Run(
 			testCase.name,
 			func(t *testing.T) {
-				actual := difflibgo.UnifiedDiff(testCase.a, testCase.b)
+				actual := difflibgo.UnifiedDiff(testCase.a, testCase.b, testCase.opts...)
 
 				if actual != testCase.expected {
 					failOutput(
@@ -114,9 +145,13 @@ z123
 xyz`,
 			expected: `abc
 [91mdefq[0m
-[91m123[0m
+[93m   -
+[0m
 [92mdef[0m
+[91m123[0m
 [92mz123[0m
+[93m+
+[0m
 xyz`,
 		},
 	}
@@ -138,3 +173,326 @@ xyz`,
 		)
 	}
 }
+
+func TestUnifiedDiffLines(t *testing.T) {
+	a := []string{
+		"one\n", "two\n", "three\n", "four\n", "five\n",
+		"six\n", "seven\n", "eight\n", "nine\n", "ten\n",
+	}
+	b := []string{
+		"one\n", "2\n", "three\n", "four\n", "five\n",
+		"six\n", "seven\n", "eight\n", "nine\n", "ten\n", "eleven\n",
+	}
+
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		opts     []difflibgo.Option
+		expected []string
+	}{
+		{
+			name:     "no-diff",
+			a:        a,
+			b:        a,
+			expected: nil,
+		},
+		{
+			name: "file-headers",
+			a:    a,
+			b:    b,
+			opts: []difflibgo.Option{
+				difflibgo.WithFromFile("a.txt", "2021-01-01"),
+				difflibgo.WithToFile("b.txt", ""),
+			},
+			expected: []string{
+				"--- a.txt\t2021-01-01\n",
+				"+++ b.txt\n",
+				"@@ -1,5 +1,5 @@\n",
+				" one\n",
+				"-two\n",
+				"+2\n",
+				" three\n",
+				" four\n",
+				" five\n",
+				"@@ -8,3 +8,4 @@\n",
+				" eight\n",
+				" nine\n",
+				" ten\n",
+				"+eleven\n",
+			},
+		},
+		{
+			name: "zero-context",
+			a:    a,
+			b:    b,
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(0),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -2 +2 @@\n",
+				"-two\n",
+				"+2\n",
+				"@@ -10,0 +11 @@\n",
+				"+eleven\n",
+			},
+		},
+		{
+			name: "no-line-term",
+			a:    []string{"a", "b"},
+			b:    []string{"a", "c"},
+			opts: []difflibgo.Option{
+				difflibgo.WithLineTerm(""),
+			},
+			expected: []string{
+				"--- ",
+				"+++ ",
+				"@@ -1,2 +1,2 @@",
+				" a",
+				"-b",
+				"+c",
+			},
+		},
+		{
+			name: "myers",
+			a:    []string{"a\n", "b\n", "c\n", "a\n", "b\n", "b\n", "a\n"},
+			b:    []string{"c\n", "b\n", "a\n", "b\n", "a\n", "c\n"},
+			opts: []difflibgo.Option{
+				difflibgo.WithAlgorithm(difflibgo.AlgorithmMyers),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -1,7 +1,6 @@\n",
+				"-a\n",
+				"+c\n",
+				" b\n",
+				"-c\n",
+				" a\n",
+				" b\n",
+				"-b\n",
+				" a\n",
+				"+c\n",
+			},
+		},
+		{
+			name: "ignore-space-change",
+			a: []string{
+				"interface Gi0/1\n", " description  uplink \n", " mtu 9000\n", "\n",
+				"interface Gi0/2\n", " shutdown\n",
+			},
+			b: []string{
+				"interface Gi0/1\n", " description uplink\n", "  mtu 9000\n",
+				"interface Gi0/2\n", " shutdown\n", "\n",
+			},
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(1),
+				difflibgo.WithIgnoreSpaceChange(true),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -3,4 +3,4 @@\n",
+				"  mtu 9000\n",
+				"-\n",
+				" interface Gi0/2\n",
+				"  shutdown\n",
+				"+\n",
+			},
+		},
+		{
+			name: "ignore-all-space-and-blank-lines",
+			a: []string{
+				"interface Gi0/1\n", " description  uplink \n", " mtu 9000\n", "\n",
+				"interface Gi0/2\n", " shutdown\n",
+			},
+			b: []string{
+				"interface Gi0/1\n", " description uplink\n", "  mtu 9000\n",
+				"interface Gi0/2\n", " shutdown\n", "  \n",
+			},
+			opts: []difflibgo.Option{
+				difflibgo.WithIgnoreAllSpace(true),
+				difflibgo.WithIgnoreBlankLines(true),
+			},
+			expected: nil,
+		},
+		{
+			name: "line-key",
+			a:    []string{"HOSTNAME R1\n", "INTERFACE GI0/1\n", " SHUTDOWN\n"},
+			b:    []string{"hostname r1\n", "interface gi0/1\n", " no shutdown\n"},
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(1),
+				difflibgo.WithLineKey(strings.ToLower),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -2,2 +2,2 @@\n",
+				" INTERFACE GI0/1\n",
+				"- SHUTDOWN\n",
+				"+ no shutdown\n",
+			},
+		},
+		{
+			name: "ignore-matching",
+			a: []string{
+				"! Last configuration change at 10:04:51 UTC Mon May 15 2023\n",
+				"hostname r1\n", "!\n", "interface Gi0/1\n", " no shutdown\n",
+			},
+			b: []string{
+				"! Last configuration change at 08:12:09 UTC Wed May 17 2023\n",
+				"! NVRAM config last updated at 08:12:10 UTC Wed May 17 2023\n",
+				"hostname r1\n", "!\n", "interface Gi0/1\n", " shutdown\n",
+			},
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(1),
+				difflibgo.WithIgnoreMatching(regexp.MustCompile(`^! .* at \d\d:\d\d:\d\d`)),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -4,2 +5,2 @@\n",
+				" interface Gi0/1\n",
+				"- no shutdown\n",
+				"+ shutdown\n",
+			},
+		},
+		{
+			name: "mask-matching",
+			a:    []string{"uptime is 1 week\n", "serial FOC1234X0AB\n", "hash 9f86d08\n"},
+			b:    []string{"uptime is 2 weeks\n", "serial FOC9876Z1CD\n", "hash 60303ae\n"},
+			opts: []difflibgo.Option{
+				difflibgo.WithMaskMatching("<serial>", regexp.MustCompile(`FOC\w+`)),
+				difflibgo.WithMaskMatching("<hash>", regexp.MustCompile(`[0-9a-f]{7}`)),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -1,3 +1,3 @@\n",
+				"-uptime is 1 week\n",
+				"+uptime is 2 weeks\n",
+				" serial FOC1234X0AB\n",
+				" hash 9f86d08\n",
+			},
+		},
+		{
+			name: "ignore-blank-lines",
+			a:    []string{"one\n", "two\n", "three\n", "four\n", "five\n"},
+			b:    []string{"one\n", "\n", "two\n", "three\n", "four\n", "five\n", "\n", "6\n"},
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(0),
+				difflibgo.WithIgnoreBlankLines(true),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -5,0 +7,2 @@\n",
+				"+\n",
+				"+6\n",
+			},
+		},
+		{
+			name: "no-newline-at-end",
+			a:    []string{"one\n", "two\n", "three"},
+			b:    []string{"one\n", "two\n", "three\n", "four"},
+			opts: []difflibgo.Option{difflibgo.WithContext(1)},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -2,2 +2,3 @@\n",
+				" two\n",
+				"-three\n",
+				"\\ No newline at end of file\n",
+				"+three\n",
+				"+four\n",
+				"\\ No newline at end of file\n",
+			},
+		},
+		{
+			name: "no-newline-at-end-context-only",
+			a:    []string{"one\n", "two\n", "three"},
+			b:    []string{"1\n", "two\n", "three"},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -1,3 +1,3 @@\n",
+				"-one\n",
+				"+1\n",
+				" two\n",
+				" three\n",
+				"\\ No newline at end of file\n",
+			},
+		},
+		{
+			name: "no-newline-at-end-single-line",
+			a:    []string{"one"},
+			b:    []string{"one\n"},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -1 +1 @@\n",
+				"-one\n",
+				"\\ No newline at end of file\n",
+				"+one\n",
+			},
+		},
+		{
+			name: "form-feed-is-not-a-newline",
+			a:    []string{"x\n", "abc\f"},
+			b:    []string{"x\n", "abd\f"},
+			opts: []difflibgo.Option{difflibgo.WithContext(0)},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -2 +2 @@\n",
+				"-abc\f\n",
+				"\\ No newline at end of file\n",
+				"+abd\f\n",
+				"\\ No newline at end of file\n",
+			},
+		},
+		{
+			name: "no-line-endings",
+			a:    []string{"one"},
+			b:    []string{"1"},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -1 +1 @@\n",
+				"-one",
+				"+1",
+			},
+		},
+		{
+			name: "normalize-line-endings",
+			a:    []string{"one\r\n", "two\r\n", "three\r\n"},
+			b:    []string{"one\n", "2\n", "three\n"},
+			opts: []difflibgo.Option{
+				difflibgo.WithContext(0),
+				difflibgo.WithNormalizeLineEndings(true),
+			},
+			expected: []string{
+				"--- \n",
+				"+++ \n",
+				"@@ -2 +2 @@\n",
+				"-two\r\n",
+				"+2\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.UnifiedDiffLines(testCase.a, testCase.b, testCase.opts...)
+
+				if strings.Join(actual, "") != strings.Join(testCase.expected, "") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
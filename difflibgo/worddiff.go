@@ -0,0 +1,237 @@
+package difflibgo
+
+import (
+	"strconv"
+	"strings"
+)
+
+// WordSegment is a piece of a WordDiffLine -- Text is common to both lines (OpEqual), deleted from
+// the a line (OpDelete) or inserted in the b line (OpInsert).
+type WordSegment struct {
+	Tag  byte
+	Text string
+}
+
+// WordDiffLine is a line of word diff output, see WordDiff. A line common to both sequences is a
+// single OpEqual segment and a line unique to a or b a single OpDelete or OpInsert segment. A
+// changed line that was paired up with a similar line is the two merged into one -- the words
+// they have in common as OpEqual segments (with the text of the a line), the words that changed
+// as OpDelete segments for the a side followed by OpInsert segments for the b side. LineA and
+// LineB are the (one based) line numbers as for DiffLine, zero if the line is not in that
+// sequence. Ignored is set for a line unique to a or b whose insertion or deletion is ignored (see
+// LineIgnored), it still has its OpDelete or OpInsert segment.
+type WordDiffLine struct {
+	LineA    int
+	LineB    int
+	Segments []WordSegment
+	Ignored  bool
+}
+
+// Changed returns true if the line has any deleted or inserted segments and is not Ignored.
+func (l WordDiffLine) Changed() bool {
+	if l.Ignored {
+		return false
+	}
+
+	for _, segment := range l.Segments {
+		if segment.Tag != OpEqual {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String renders the line the way git diff --word-diff=plain does, deleted text wrapped in
+// "[-" and "-]" and inserted text in "{+" and "+}". The line terminator is kept outside of the
+// markers and written once, at the end of the line -- that of the b side if it has one. A
+// deleted or inserted segment that is only a line terminator (say "\r\n" changed to "\n", or a
+// missing final newline) is shown escaped, "[-\r\n-]{+\n+}".
+func (l WordDiffLine) String() string {
+	var rendered strings.Builder
+
+	lineTerminator := ""
+
+	for _, segment := range l.Segments {
+		text, terminator := splitLineEnding(segment.Text)
+
+		start, end := "", ""
+
+		switch segment.Tag {
+		case OpDelete:
+			start, end = wordDeleteStart, wordDeleteEnd
+		case OpInsert:
+			start, end = wordInsertStart, wordInsertEnd
+		default:
+			rendered.WriteString(text)
+
+			if terminator != "" {
+				lineTerminator = terminator
+			}
+
+			continue
+		}
+
+		if text == "" && terminator != "" {
+			text = strings.Trim(strconv.Quote(terminator), `"`)
+		}
+
+		if text != "" {
+			rendered.WriteString(start + text + end)
+		}
+
+		if terminator != "" && (lineTerminator == "" || segment.Tag == OpInsert) {
+			lineTerminator = terminator
+		}
+	}
+
+	return rendered.String() + lineTerminator
+}
+
+// splitLineEnding splits line into its text and its "\n" or "\r\n" terminator, if any.
+func splitLineEnding(line string) (text, terminator string) {
+	switch {
+	case strings.HasSuffix(line, "\r\n"):
+		return line[:len(line)-2], "\r\n"
+	case hasLineEnding(line):
+		return line[:len(line)-1], "\n"
+	}
+
+	return line, ""
+}
+
+// mergedSegments returns the segments of the line that aelt and belt, two similar lines, merge
+// into. The line terminators are split off before the words are compared, so that they never end
+// up in a changed word, and end the merged line as segments of their own.
+func (d *Differ) mergedSegments(s *SequenceMatcher[string], aelt, belt string) []WordSegment {
+	aText, aTerminator := splitLineEnding(aelt)
+	bText, bTerminator := splitLineEnding(belt)
+
+	aChars, bChars := d.splitChars(aText), d.splitChars(bText)
+
+	// as for the "?" guides, compare the keys if they line up word for word with the lines
+	aKeyChars, bKeyChars := d.splitChars(d.key(aText)), d.splitChars(d.key(bText))
+
+	if len(aKeyChars) == len(aChars) && len(bKeyChars) == len(bChars) {
+		s.SetSeqs(aKeyChars, bKeyChars)
+	} else {
+		s.SetSeqs(aChars, bChars)
+	}
+
+	segments := wordSegments(s.GetOpcodes(), aText, bText, charOffsets(aChars), charOffsets(bChars))
+
+	if aTerminator == bTerminator {
+		if aTerminator != "" {
+			segments = append(segments, WordSegment{Tag: OpEqual, Text: aTerminator})
+		}
+
+		return segments
+	}
+
+	if aTerminator != "" {
+		segments = append(segments, WordSegment{Tag: OpDelete, Text: aTerminator})
+	}
+
+	if bTerminator != "" {
+		segments = append(segments, WordSegment{Tag: OpInsert, Text: bTerminator})
+	}
+
+	return segments
+}
+
+// wordSegments returns the segments of the merged line for the intraline opCodes of aelt and belt,
+// aOffsets and bOffsets are the byte offsets of the words the opCodes index.
+func wordSegments(opCodes []OpCode, aelt, belt string, aOffsets, bOffsets []int) []WordSegment {
+	segments := make([]WordSegment, 0, len(opCodes))
+
+	for _, opCode := range opCodes {
+		aText := aelt[aOffsets[opCode.SeqALo]:aOffsets[opCode.SeqAHi]]
+		bText := belt[bOffsets[opCode.SeqBLo]:bOffsets[opCode.SeqBHi]]
+
+		switch opCode.Tag {
+		case OpEqual:
+			segments = append(segments, WordSegment{Tag: OpEqual, Text: aText})
+		case OpReplace:
+			segments = append(
+				segments,
+				WordSegment{Tag: OpDelete, Text: aText},
+				WordSegment{Tag: OpInsert, Text: bText},
+			)
+		case OpDelete:
+			segments = append(segments, WordSegment{Tag: OpDelete, Text: aText})
+		case OpInsert:
+			segments = append(segments, WordSegment{Tag: OpInsert, Text: bText})
+		}
+	}
+
+	return segments
+}
+
+// WordDiff compares a and b word by word (see WithWordDiff) and returns every line with the changes
+// marked up inline, like git diff --word-diff does -- "interface Gi0/[-1-]{+22+}" rather than a
+// deleted line, an inserted line and their "?" guides. Lines that have nothing in common with a
+// line on the other side are wrapped as a whole, "[-line-]" or "{+line+}". Accepts the options of
+// NewDiffer.
+func WordDiff(a, b []string, opts ...Option) []string {
+	wordDiffLines := WordDiffStructured(a, b, opts...)
+
+	rendered := make([]string, len(wordDiffLines))
+
+	for idx, wordDiffLine := range wordDiffLines {
+		rendered[idx] = wordDiffLine.String()
+	}
+
+	return rendered
+}
+
+// WordDiffStructured is WordDiff returning the lines as WordDiffLines rather than pre-formatted
+// strings.
+func WordDiffStructured(a, b []string, opts ...Option) []WordDiffLine {
+	var wordDiffLines []WordDiffLine
+
+	_ = wordDiffer(opts...).compareWords(a, b, func(wordDiffLine WordDiffLine) error {
+		wordDiffLines = append(wordDiffLines, wordDiffLine)
+
+		return nil
+	})
+
+	return wordDiffLines
+}
+
+// wordDiffer returns the Differ of WordDiff, the word diff mode always applies.
+func wordDiffer(opts ...Option) *Differ {
+	return NewDiffer(append(opts[:len(opts):len(opts)], WithWordDiff(true))...)
+}
+
+// compareWords is CompareFunc for word diffs, fn is called with each WordDiffLine as soon as it is
+// produced.
+func (d *Differ) compareWords(seqA, seqB []string, fn func(WordDiffLine) error) error {
+	merging := *d
+	merging.emitMerged = fn
+
+	return merging.CompareFunc(seqA, seqB, func(diffLine DiffLine) error {
+		tag, ignored := OpEqual, false
+
+		switch diffLine.Kind {
+		case LineDelete:
+			tag = OpDelete
+		case LineInsert:
+			tag = OpInsert
+		case LineIgnored:
+			ignored = true
+			tag = OpInsert
+
+			if diffLine.LineA != 0 {
+				tag = OpDelete
+			}
+		case LineEqual, LineHint:
+		}
+
+		return fn(WordDiffLine{
+			LineA:    diffLine.LineA,
+			LineB:    diffLine.LineB,
+			Segments: []WordSegment{{Tag: tag, Text: diffLine.Text}},
+			Ignored:  ignored,
+		})
+	})
+}
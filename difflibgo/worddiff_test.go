@@ -0,0 +1,149 @@
+package difflibgo_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/carlmontanari/difflibgo/difflibgo"
+)
+
+func TestWordDiff(t *testing.T) {
+	cases := []struct {
+		name     string
+		a        []string
+		b        []string
+		opts     []difflibgo.Option
+		expected []string
+	}{
+		{
+			name:     "no-diff",
+			a:        []string{"one\n", "two\n"},
+			b:        []string{"one\n", "two\n"},
+			expected: []string{"one\n", "two\n"},
+		},
+		{
+			name: "changed-words",
+			a: []string{
+				"hostname r1\n",
+				"description the quick brown fox jumps over the lazy dog\n",
+			},
+			b: []string{
+				"hostname r1\n",
+				"description the quick red fox leaps over the lazy dogs\n",
+			},
+			expected: []string{
+				"hostname r1\n",
+				"description the quick [-brown-]{+red+} fox [-jumps-]{+leaps+} over the lazy " +
+					"[-dog-]{+dogs+}\n",
+			},
+		},
+		{
+			name: "inserted-and-deleted-words",
+			a:    []string{"interface Gi0/1\n", "ip address 10.0.0.1 255.255.255.0\n"},
+			b:    []string{"interface Gi0/1\n", "ip address 10.0.0.1 255.255.255.0 secondary\n"},
+			expected: []string{
+				"interface Gi0/1\n",
+				"ip address 10.0.0.1 255.255.255.0{+ secondary+}\n",
+			},
+		},
+		{
+			name: "unpaired-lines",
+			a:    []string{"one\n", "two three\n", "four\n"},
+			b:    []string{"one\n", "two 3\n", "five\n", "four\n"},
+			expected: []string{
+				"one\n",
+				"two [-three-]{+3+}\n",
+				"{+five+}\n",
+				"four\n",
+			},
+		},
+		{
+			name:     "trailing-whitespace",
+			a:        []string{"interface x y  \n"},
+			b:        []string{"interface x y\n"},
+			expected: []string{"interface x y[-  -]\n"},
+		},
+		{
+			name:     "crlf-to-lf",
+			a:        []string{"description uplink\r\n"},
+			b:        []string{"description uplink\n"},
+			expected: []string{"description uplink[-\\r\\n-]{+\\n+}\n"},
+		},
+		{
+			name:     "missing-final-newline",
+			a:        []string{"one\n", "description uplink\n"},
+			b:        []string{"one\n", "description uplink"},
+			expected: []string{"one\n", "description uplink[-\\n-]\n"},
+		},
+		{
+			name:     "added-final-newline",
+			a:        []string{"description uplink"},
+			b:        []string{"description uplink\n"},
+			expected: []string{"description uplink{+\\n+}\n"},
+		},
+		{
+			name: "ignore-space-change",
+			a:    []string{"hostname  r1\n", "description foo\n"},
+			b:    []string{"hostname r1\n", "description bar\n"},
+			opts: []difflibgo.Option{difflibgo.WithIgnoreSpaceChange(true)},
+			expected: []string{
+				"hostname  r1\n",
+				"[-description foo-]\n",
+				"{+description bar+}\n",
+			},
+		},
+	}
+
+	for _, testCase := range cases {
+		t.Run(
+			testCase.name,
+			func(t *testing.T) {
+				actual := difflibgo.WordDiff(testCase.a, testCase.b, testCase.opts...)
+
+				if strings.Join(actual, "") != strings.Join(testCase.expected, "") {
+					failOutput(t, actual, testCase.expected)
+				}
+			},
+		)
+	}
+}
+
+func TestWordDiffStructured(t *testing.T) {
+	actual := difflibgo.WordDiffStructured(
+		[]string{"interface Gi0/1", "ip address 10.0.0.1 255.255.255.0"},
+		[]string{"interface Gi0/22", "no ip address 10.0.0.1 255.255.255.0"},
+	)
+
+	expected := []difflibgo.WordDiffLine{
+		{
+			LineA: 1,
+			LineB: 1,
+			Segments: []difflibgo.WordSegment{
+				{Tag: difflibgo.OpEqual, Text: "interface Gi0/"},
+				{Tag: difflibgo.OpDelete, Text: "1"},
+				{Tag: difflibgo.OpInsert, Text: "22"},
+			},
+		},
+		{
+			LineA: 2,
+			LineB: 2,
+			Segments: []difflibgo.WordSegment{
+				{Tag: difflibgo.OpInsert, Text: "no "},
+				{Tag: difflibgo.OpEqual, Text: "ip address 10.0.0.1 255.255.255.0"},
+			},
+		},
+	}
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Fatalf(
+			"actual and expected do not match...\nactual: %+v\nexpected: %+v",
+			actual,
+			expected,
+		)
+	}
+
+	if !actual[0].Changed() || actual[1].String() != "{+no +}ip address 10.0.0.1 255.255.255.0" {
+		t.Fatalf("unexpected rendering of %+v", actual)
+	}
+}